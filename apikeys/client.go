@@ -53,36 +53,40 @@ func NewClient(cfg *config.Config) *Client {
 // List retrieves all API keys for the instance.
 func (c *Client) List(ctx context.Context) ([]APIKey, error) {
 	path := fmt.Sprintf("/instances/%s/api-keys", c.instanceID)
-	return request.Do[[]APIKey](c.cfg, ctx, "GET", path, nil)
+	return request.Do[[]APIKey](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("apikeys.List", "/instances/{instance_id}/api-keys"))
 }
 
 // Create creates a new API key.
 func (c *Client) Create(ctx context.Context, params *CreateParams) (*CreateResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/api-keys", c.instanceID)
-	return request.Do[*CreateResponse](c.cfg, ctx, "POST", path, params)
+	return request.Do[*CreateResponse](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("apikeys.Create", "/instances/{instance_id}/api-keys"))
 }
 
 // Get retrieves a specific API key by ID.
 func (c *Client) Get(ctx context.Context, id string) (*APIKey, error) {
 	if id == "" {
-		return nil, fmt.Errorf("id cannot be empty")
+		return nil, request.EmptyParam("id")
 	}
 
 	path := fmt.Sprintf("/instances/%s/api-keys/%s", c.instanceID, id)
-	return request.Do[*APIKey](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*APIKey](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("apikeys.Get", "/instances/{instance_id}/api-keys/{id}"))
 }
 
 // Delete deletes an API key.
 func (c *Client) Delete(ctx context.Context, id string) error {
 	if id == "" {
-		return fmt.Errorf("id cannot be empty")
+		return request.EmptyParam("id")
 	}
 
 	path := fmt.Sprintf("/instances/%s/api-keys/%s", c.instanceID, id)
-	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil)
+	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil,
+		request.WithOperation("apikeys.Delete", "/instances/{instance_id}/api-keys/{id}"))
 	return err
 }
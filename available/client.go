@@ -36,16 +36,18 @@ func NewClient(cfg *config.Config) *Client {
 // GetBankDetails retrieves the bank details configuration for a specific rail.
 func (c *Client) GetBankDetails(ctx context.Context, rail types.Rail) ([]types.BankDetail, error) {
 	if rail == "" {
-		return nil, fmt.Errorf("rail cannot be empty")
+		return nil, request.EmptyParam("rail")
 	}
 
 	path := fmt.Sprintf("/available/bank-details?rail=%s", rail)
-	return request.Do[[]types.BankDetail](c.cfg, ctx, "GET", path, nil)
+	return request.Do[[]types.BankDetail](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("available.GetBankDetails", "/available/bank-details"))
 }
 
 // GetRails retrieves all available payment rails.
 func (c *Client) GetRails(ctx context.Context) ([]types.RailEntry, error) {
-	return request.Do[[]types.RailEntry](c.cfg, ctx, "GET", "/available/rails", nil)
+	return request.Do[[]types.RailEntry](c.cfg, ctx, "GET", "/available/rails", nil,
+		request.WithOperation("available.GetRails", "/available/rails"))
 }
 
 // NaicsCode represents a NAICS business industry code.
@@ -56,16 +58,18 @@ type NaicsCode struct {
 
 // GetNaicsCodes retrieves the available NAICS business industry codes.
 func (c *Client) GetNaicsCodes(ctx context.Context) ([]NaicsCode, error) {
-	return request.Do[[]NaicsCode](c.cfg, ctx, "GET", "/available/naics", nil)
+	return request.Do[[]NaicsCode](c.cfg, ctx, "GET", "/available/naics", nil,
+		request.WithOperation("available.GetNaicsCodes", "/available/naics"))
 }
 
 // GetSwiftCodeBankDetails retrieves the bank details of a specific swift code.
 func (c *Client) GetSwiftCodeBankDetails(ctx context.Context, swift string) ([]GetSwiftCodeBankDetailsResponse, error) {
 	if swift == "" {
-		return nil, fmt.Errorf("swift code cannot be empty")
+		return nil, request.EmptyParam("swift code")
 	}
 
 	path := fmt.Sprintf("/available/swift/%s", swift)
 
-	return request.Do[[]GetSwiftCodeBankDetailsResponse](c.cfg, ctx, "GET", path, nil)
+	return request.Do[[]GetSwiftCodeBankDetailsResponse](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("available.GetSwiftCodeBankDetails", "/available/swift/{swift_code}"))
 }
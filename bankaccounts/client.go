@@ -123,18 +123,20 @@ type GetResponse struct {
 
 // CreatePixParams represents parameters for creating a PIX bank account.
 type CreatePixParams struct {
-	ReceiverID string `json:"-"`
-	Name       string `json:"name"`
-	PixKey     string `json:"pix_key"`
+	ReceiverID     string `json:"-"`
+	Name           string `json:"name"`
+	PixKey         string `json:"pix_key"`
+	IdempotencyKey string `json:"-"`
 }
 
 // CreatePixResponse represents the response when creating a PIX bank account.
 type CreatePixResponse struct {
-	ID        string    `json:"id"`
-	Type      string    `json:"type"`
-	Name      string    `json:"name"`
-	PixKey    string    `json:"pix_key"`
-	CreatedAt time.Time `json:"created_at"`
+	ID             string    `json:"id"`
+	Type           string    `json:"type"`
+	Name           string    `json:"name"`
+	PixKey         string    `json:"pix_key"`
+	CreatedAt      time.Time `json:"created_at"`
+	IdempotencyKey string    `json:"-"`
 }
 
 // CreateArgentinaTransfersResponse represents the response when creating an Argentina transfers bank account.
@@ -146,6 +148,7 @@ type CreateArgentinaTransfersResponse struct {
 	TransfersType    ArgentinaTransfers `json:"transfers_type"`
 	TransfersAccount string             `json:"transfers_account"`
 	CreatedAt        time.Time          `json:"created_at"`
+	IdempotencyKey   string             `json:"-"`
 }
 
 // CreateSpeiResponse represents the response when creating a SPEI bank account.
@@ -158,6 +161,7 @@ type CreateSpeiResponse struct {
 	SpeiInstitutionCode string       `json:"spei_institution_code"`
 	SpeiClabe           string       `json:"spei_clabe"`
 	CreatedAt           time.Time    `json:"created_at"`
+	IdempotencyKey      string       `json:"-"`
 }
 
 // CreateColombiaAchResponse represents the response when creating a Colombia ACH bank account.
@@ -174,6 +178,7 @@ type CreateColombiaAchResponse struct {
 	AchCopBankCode             string                `json:"ach_cop_bank_code"`
 	AchCopBankAccount          string                `json:"ach_cop_bank_account"`
 	CreatedAt                  time.Time             `json:"created_at"`
+	IdempotencyKey             string                `json:"-"`
 }
 
 // CreateAchResponse represents the response when creating an ACH bank account.
@@ -201,6 +206,7 @@ type CreateAchResponse struct {
 	AchCopBankCode             *string                     `json:"ach_cop_bank_code"`
 	AchCopBankAccount          *string                     `json:"ach_cop_bank_account"`
 	CreatedAt                  time.Time                   `json:"created_at"`
+	IdempotencyKey             string                      `json:"-"`
 }
 
 // CreateWireResponse represents the response when creating a Wire bank account.
@@ -220,6 +226,7 @@ type CreateWireResponse struct {
 	Country               types.Country               `json:"country"`
 	PostalCode            string                      `json:"postal_code"`
 	CreatedAt             time.Time                   `json:"created_at"`
+	IdempotencyKey        string                      `json:"-"`
 }
 
 // CreateInternationalSwiftResponse represents the response when creating an international SWIFT bank account.
@@ -258,6 +265,7 @@ type CreateInternationalSwiftResponse struct {
 	SwiftIntermediaryBankCountry           *types.Country              `json:"swift_intermediary_bank_country"`
 	SwiftPaymentCode                       *string                     `json:"swift_payment_code"`
 	CreatedAt                              time.Time                   `json:"created_at"`
+	IdempotencyKey                         string                      `json:"-"`
 }
 
 // CreateRtpResponse represents the response when creating an RTP bank account.
@@ -277,6 +285,7 @@ type CreateRtpResponse struct {
 	Country               types.Country               `json:"country"`
 	PostalCode            string                      `json:"postal_code"`
 	CreatedAt             time.Time                   `json:"created_at"`
+	IdempotencyKey        string                      `json:"-"`
 }
 
 // CreateTedParams represents parameters for creating a TED bank account.
@@ -299,6 +308,7 @@ type CreateTedParams struct {
 	PhoneNumber           string                      `json:"phone_number,omitempty"`
 	TaxID                 string                      `json:"tax_id,omitempty"`
 	DateOfBirth           string                      `json:"date_of_birth,omitempty"`
+	IdempotencyKey        string                      `json:"-"`
 }
 
 // CreateTedResponse represents the response when creating a TED bank account.
@@ -319,6 +329,7 @@ type CreateTedResponse struct {
 	Country               types.Country               `json:"country"`
 	PostalCode            string                      `json:"postal_code"`
 	CreatedAt             time.Time                   `json:"created_at"`
+	IdempotencyKey        string                      `json:"-"`
 }
 
 // CreatePixSafeParams represents parameters for creating a PIX Safe bank account.
@@ -331,6 +342,7 @@ type CreatePixSafeParams struct {
 	PixSafeBankCode   string                `json:"pix_safe_bank_code"`
 	PixSafeBranchCode string                `json:"pix_safe_branch_code"`
 	PixSafeCpfCnpj    string                `json:"pix_safe_cpf_cnpj"`
+	IdempotencyKey    string                `json:"-"`
 }
 
 // CreatePixSafeResponse represents the response when creating a PIX Safe bank account.
@@ -345,6 +357,7 @@ type CreatePixSafeResponse struct {
 	PixSafeBranchCode string                `json:"pix_safe_branch_code"`
 	PixSafeCpfCnpj    string                `json:"pix_safe_cpf_cnpj"`
 	CreatedAt         time.Time             `json:"created_at"`
+	IdempotencyKey    string                `json:"-"`
 }
 
 // ListParams represents parameters for listing bank accounts with optional filters.
@@ -377,6 +390,7 @@ type CreateAchParams struct {
 	PhoneNumber           string                      `json:"phone_number,omitempty"`
 	TaxID                 string                      `json:"tax_id,omitempty"`
 	DateOfBirth           string                      `json:"date_of_birth,omitempty"`
+	IdempotencyKey        string                      `json:"-"`
 }
 
 // CreateWireParams represents parameters for creating a Wire bank account.
@@ -398,6 +412,7 @@ type CreateWireParams struct {
 	PhoneNumber           string                      `json:"phone_number,omitempty"`
 	TaxID                 string                      `json:"tax_id,omitempty"`
 	DateOfBirth           string                      `json:"date_of_birth,omitempty"`
+	IdempotencyKey        string                      `json:"-"`
 }
 
 // CreateArgentinaTransfersParams represents parameters for creating an Argentina transfers bank account.
@@ -407,6 +422,7 @@ type CreateArgentinaTransfersParams struct {
 	BeneficiaryName  string             `json:"beneficiary_name"`
 	TransfersAccount string             `json:"transfers_account"`
 	TransfersType    ArgentinaTransfers `json:"transfers_type"`
+	IdempotencyKey   string             `json:"-"`
 }
 
 // CreateSpeiParams represents parameters for creating a SPEI bank account.
@@ -417,6 +433,7 @@ type CreateSpeiParams struct {
 	SpeiClabe           string       `json:"spei_clabe"`
 	SpeiInstitutionCode string       `json:"spei_institution_code"`
 	SpeiProtocol        SpeiProtocol `json:"spei_protocol"`
+	IdempotencyKey      string       `json:"-"`
 }
 
 // CreateColombiaAchParams represents parameters for creating a Colombia ACH bank account.
@@ -431,6 +448,7 @@ type CreateColombiaAchParams struct {
 	AchCopEmail                string                `json:"ach_cop_email"`
 	AchCopBankCode             string                `json:"ach_cop_bank_code"`
 	AchCopBankAccount          string                `json:"ach_cop_bank_account"`
+	IdempotencyKey             string                `json:"-"`
 }
 
 // CreateInternationalSwiftParams represents parameters for creating an international SWIFT bank account.
@@ -464,6 +482,7 @@ type CreateInternationalSwiftParams struct {
 	PhoneNumber                            string                      `json:"phone_number,omitempty"`
 	TaxID                                  string                      `json:"tax_id,omitempty"`
 	DateOfBirth                            string                      `json:"date_of_birth,omitempty"`
+	IdempotencyKey                         string                      `json:"-"`
 }
 
 // CreateRtpParams represents parameters for creating an RTP (Real-Time Payments) bank account.
@@ -485,6 +504,7 @@ type CreateRtpParams struct {
 	PhoneNumber           string                      `json:"phone_number,omitempty"`
 	TaxID                 string                      `json:"tax_id,omitempty"`
 	DateOfBirth           string                      `json:"date_of_birth,omitempty"`
+	IdempotencyKey        string                      `json:"-"`
 }
 
 // Client handles bank account-related operations.
@@ -504,10 +524,10 @@ func NewClient(cfg *config.Config) *Client {
 // List retrieves all bank accounts for a receiver with optional filters.
 func (c *Client) List(ctx context.Context, params *ListParams) ([]BankAccount, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/bank-accounts", c.instanceID, params.ReceiverID)
@@ -532,43 +552,47 @@ func (c *Client) List(ctx context.Context, params *ListParams) ([]BankAccount, e
 		path += "?" + q.Encode()
 	}
 
-	return request.Do[[]BankAccount](c.cfg, ctx, "GET", path, nil)
+	return request.Do[[]BankAccount](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("bankaccounts.List", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts"),
+		request.WithParams(params))
 }
 
 // Get retrieves a specific bank account.
 func (c *Client) Get(ctx context.Context, receiverID, id string) (*GetResponse, error) {
 	if receiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 	if id == "" {
-		return nil, fmt.Errorf("id cannot be empty")
+		return nil, request.EmptyParam("id")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/bank-accounts/%s", c.instanceID, receiverID, id)
-	return request.Do[*GetResponse](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*GetResponse](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("bankaccounts.Get", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts/{id}"))
 }
 
 // Delete deletes a bank account.
 func (c *Client) Delete(ctx context.Context, receiverID, id string) error {
 	if receiverID == "" {
-		return fmt.Errorf("receiver ID cannot be empty")
+		return request.EmptyParam("receiver ID")
 	}
 	if id == "" {
-		return fmt.Errorf("id cannot be empty")
+		return request.EmptyParam("id")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/bank-accounts/%s", c.instanceID, receiverID, id)
-	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil)
+	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil,
+		request.WithOperation("bankaccounts.Delete", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts/{id}"))
 	return err
 }
 
 // CreatePix creates a PIX bank account.
 func (c *Client) CreatePix(ctx context.Context, params *CreatePixParams) (*CreatePixResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/bank-accounts", c.instanceID, params.ReceiverID)
@@ -579,16 +603,28 @@ func (c *Client) CreatePix(ctx context.Context, params *CreatePixParams) (*Creat
 		"pix_key": params.PixKey,
 	}
 
-	return request.Do[*CreatePixResponse](c.cfg, ctx, "POST", path, body)
+	var key string
+	resp, err := request.Do[*CreatePixResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("bankaccounts.CreatePix", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts"),
+		request.WithParams(params),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // CreateAch creates an ACH bank account.
 func (c *Client) CreateAch(ctx context.Context, params *CreateAchParams) (*CreateAchResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/bank-accounts", c.instanceID, params.ReceiverID)
@@ -625,16 +661,28 @@ func (c *Client) CreateAch(ctx context.Context, params *CreateAchParams) (*Creat
 		body["date_of_birth"] = params.DateOfBirth
 	}
 
-	return request.Do[*CreateAchResponse](c.cfg, ctx, "POST", path, body)
+	var key string
+	resp, err := request.Do[*CreateAchResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("bankaccounts.CreateAch", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts"),
+		request.WithParams(params),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // CreateWire creates a Wire bank account.
 func (c *Client) CreateWire(ctx context.Context, params *CreateWireParams) (*CreateWireResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/bank-accounts", c.instanceID, params.ReceiverID)
@@ -670,16 +718,28 @@ func (c *Client) CreateWire(ctx context.Context, params *CreateWireParams) (*Cre
 		body["date_of_birth"] = params.DateOfBirth
 	}
 
-	return request.Do[*CreateWireResponse](c.cfg, ctx, "POST", path, body)
+	var key string
+	resp, err := request.Do[*CreateWireResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("bankaccounts.CreateWire", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts"),
+		request.WithParams(params),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // CreateArgentinaTransfers creates an Argentina transfers bank account.
 func (c *Client) CreateArgentinaTransfers(ctx context.Context, params *CreateArgentinaTransfersParams) (*CreateArgentinaTransfersResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/bank-accounts", c.instanceID, params.ReceiverID)
@@ -692,16 +752,28 @@ func (c *Client) CreateArgentinaTransfers(ctx context.Context, params *CreateArg
 		"transfers_type":    params.TransfersType,
 	}
 
-	return request.Do[*CreateArgentinaTransfersResponse](c.cfg, ctx, "POST", path, body)
+	var key string
+	resp, err := request.Do[*CreateArgentinaTransfersResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("bankaccounts.CreateArgentinaTransfers", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts"),
+		request.WithParams(params),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // CreateSpei creates a SPEI bank account.
 func (c *Client) CreateSpei(ctx context.Context, params *CreateSpeiParams) (*CreateSpeiResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/bank-accounts", c.instanceID, params.ReceiverID)
@@ -715,16 +787,28 @@ func (c *Client) CreateSpei(ctx context.Context, params *CreateSpeiParams) (*Cre
 		"spei_protocol":         params.SpeiProtocol,
 	}
 
-	return request.Do[*CreateSpeiResponse](c.cfg, ctx, "POST", path, body)
+	var key string
+	resp, err := request.Do[*CreateSpeiResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("bankaccounts.CreateSpei", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts"),
+		request.WithParams(params),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // CreateColombiaAch creates a Colombia ACH bank account.
 func (c *Client) CreateColombiaAch(ctx context.Context, params *CreateColombiaAchParams) (*CreateColombiaAchResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/bank-accounts", c.instanceID, params.ReceiverID)
@@ -742,16 +826,28 @@ func (c *Client) CreateColombiaAch(ctx context.Context, params *CreateColombiaAc
 		"ach_cop_bank_account":           params.AchCopBankAccount,
 	}
 
-	return request.Do[*CreateColombiaAchResponse](c.cfg, ctx, "POST", path, body)
+	var key string
+	resp, err := request.Do[*CreateColombiaAchResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("bankaccounts.CreateColombiaAch", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts"),
+		request.WithParams(params),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // CreateInternationalSwift creates an international SWIFT bank account.
 func (c *Client) CreateInternationalSwift(ctx context.Context, params *CreateInternationalSwiftParams) (*CreateInternationalSwiftResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/bank-accounts", c.instanceID, params.ReceiverID)
@@ -803,16 +899,28 @@ func (c *Client) CreateInternationalSwift(ctx context.Context, params *CreateInt
 		body["date_of_birth"] = params.DateOfBirth
 	}
 
-	return request.Do[*CreateInternationalSwiftResponse](c.cfg, ctx, "POST", path, body)
+	var key string
+	resp, err := request.Do[*CreateInternationalSwiftResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("bankaccounts.CreateInternationalSwift", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts"),
+		request.WithParams(params),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // CreatePixSafe creates a PIX Safe bank account.
 func (c *Client) CreatePixSafe(ctx context.Context, params *CreatePixSafeParams) (*CreatePixSafeResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/bank-accounts", c.instanceID, params.ReceiverID)
@@ -828,16 +936,28 @@ func (c *Client) CreatePixSafe(ctx context.Context, params *CreatePixSafeParams)
 		"pix_safe_cpf_cnpj":    params.PixSafeCpfCnpj,
 	}
 
-	return request.Do[*CreatePixSafeResponse](c.cfg, ctx, "POST", path, body)
+	var key string
+	resp, err := request.Do[*CreatePixSafeResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("bankaccounts.CreatePixSafe", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts"),
+		request.WithParams(params),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // CreateRtp creates an RTP (Real-Time Payments) bank account.
 func (c *Client) CreateRtp(ctx context.Context, params *CreateRtpParams) (*CreateRtpResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/bank-accounts", c.instanceID, params.ReceiverID)
@@ -873,16 +993,28 @@ func (c *Client) CreateRtp(ctx context.Context, params *CreateRtpParams) (*Creat
 		body["date_of_birth"] = params.DateOfBirth
 	}
 
-	return request.Do[*CreateRtpResponse](c.cfg, ctx, "POST", path, body)
+	var key string
+	resp, err := request.Do[*CreateRtpResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("bankaccounts.CreateRtp", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts"),
+		request.WithParams(params),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // CreateTed creates a TED bank account.
 func (c *Client) CreateTed(ctx context.Context, params *CreateTedParams) (*CreateTedResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/bank-accounts", c.instanceID, params.ReceiverID)
@@ -919,5 +1051,17 @@ func (c *Client) CreateTed(ctx context.Context, params *CreateTedParams) (*Creat
 		body["date_of_birth"] = params.DateOfBirth
 	}
 
-	return request.Do[*CreateTedResponse](c.cfg, ctx, "POST", path, body)
+	var key string
+	resp, err := request.Do[*CreateTedResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("bankaccounts.CreateTed", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts"),
+		request.WithParams(params),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
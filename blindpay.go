@@ -2,6 +2,7 @@ package blindpay
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/blindpaylabs/blindpay-go/fees"
 	"github.com/blindpaylabs/blindpay-go/instances"
 	"github.com/blindpaylabs/blindpay-go/internal/config"
+	"github.com/blindpaylabs/blindpay-go/internal/request"
 	"github.com/blindpaylabs/blindpay-go/partnerfees"
 	"github.com/blindpaylabs/blindpay-go/payins"
 	"github.com/blindpaylabs/blindpay-go/payouts"
@@ -30,10 +32,20 @@ const Version = "1.12.0"
 
 // Client is the main BlindPay client.
 type Client struct {
-	baseURL    string
-	apiKey     string
-	instanceID string
-	httpClient *http.Client
+	baseURL     string
+	apiKey      string
+	instanceID  string
+	httpClient  *http.Client
+	retry       *request.RetryPolicy
+	middleware  []Middleware
+	logger      *slog.Logger
+	metrics     Metrics
+	rateLimit   *RateLimit
+	breaker     *CircuitBreakerPolicy
+	environment Environment
+	credentials CredentialProvider
+	cache       *CachePolicy
+	cfg         *config.Config
 
 	Available        *available.Client
 	APIKeys          *apikeys.Client
@@ -56,15 +68,8 @@ type Client struct {
 }
 
 // New creates a new BlindPay client with the given API key and instance ID.
+// The API key may be empty when WithCredentials is given.
 func New(apiKey, instanceID string, opts ...Option) (*Client, error) {
-	if apiKey == "" {
-		return nil, fmt.Errorf("api key not provided, get your api key on blindpay dashboard")
-	}
-
-	if instanceID == "" {
-		return nil, fmt.Errorf("instance id not provided, get your instance id on blindpay dashboard")
-	}
-
 	c := &Client{
 		baseURL:    "https://api.blindpay.com/v1",
 		apiKey:     apiKey,
@@ -78,14 +83,57 @@ func New(apiKey, instanceID string, opts ...Option) (*Client, error) {
 		opt(c)
 	}
 
+	if c.apiKey == "" && c.credentials == nil {
+		return nil, fmt.Errorf("api key not provided, get your api key on blindpay dashboard")
+	}
+
+	if c.instanceID == "" {
+		return nil, fmt.Errorf("instance id not provided, get your instance id on blindpay dashboard")
+	}
+
+	env, err := request.ParseEnvironment(string(c.environment))
+	if err != nil {
+		return nil, err
+	}
+	c.environment = env
+
+	middleware := c.middleware
+	if c.logger != nil {
+		middleware = append(middleware[:len(middleware):len(middleware)], request.Logging(c.logger))
+	}
+	if c.environment != "" {
+		middleware = append(middleware[:len(middleware):len(middleware)], request.EnvironmentGuard(c.environment))
+	}
+
 	cfg := &config.Config{
-		BaseURL:    c.baseURL,
-		APIKey:     c.apiKey,
-		InstanceID: c.instanceID,
-		HTTPClient: c.httpClient,
-		UserAgent:  c.userAgent(),
+		BaseURL:     c.baseURL,
+		APIKey:      c.apiKey,
+		InstanceID:  c.instanceID,
+		HTTPClient:  c.httpClient,
+		UserAgent:   c.userAgent(),
+		Retry:       c.retry,
+		Middleware:  middleware,
+		Metrics:     c.metrics,
+		Credentials: c.credentials,
+	}
+	if c.rateLimit != nil {
+		cfg.RateLimiter = request.NewRateLimiter(*c.rateLimit)
+	}
+	if c.breaker != nil {
+		cfg.CircuitBreaker = request.NewCircuitBreaker(*c.breaker)
+	}
+	if c.cache != nil {
+		cfg.Cache = request.NewCache(*c.cache)
 	}
 
+	c.init(cfg)
+
+	return c, nil
+}
+
+// init creates the sub-clients from cfg.
+func (c *Client) init(cfg *config.Config) {
+	c.cfg = cfg
 	c.Available = available.NewClient(cfg)
 	c.APIKeys = apikeys.NewClient(cfg)
 	c.BankAccounts = bankaccounts.NewClient(cfg)
@@ -104,8 +152,6 @@ func New(apiKey, instanceID string, opts ...Option) (*Client, error) {
 	c.Wallets = wallets.NewClient(cfg)
 	c.OfframpWallets = wallets.NewOfframpClient(cfg)
 	c.WebhookEndpoints = webhookendpoints.NewClient(cfg)
-
-	return c, nil
 }
 
 // userAgent returns the User-Agent string for requests.
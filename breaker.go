@@ -0,0 +1,24 @@
+package blindpay
+
+import (
+	"github.com/blindpaylabs/blindpay-go/internal/request"
+)
+
+// ErrCircuitOpen is matched by errors.Is when a call is rejected because the
+// circuit breaker of its endpoint group is open.
+var ErrCircuitOpen = request.ErrCircuitOpen
+
+// CircuitOpenError reports a call rejected by an open circuit breaker.
+// It is an alias to the internal request.CircuitOpenError for convenience.
+type CircuitOpenError = request.CircuitOpenError
+
+// CircuitBreakerPolicy controls when the circuit breaker opens and how it recovers.
+// It is an alias to the internal request.CircuitBreakerPolicy for convenience.
+type CircuitBreakerPolicy = request.CircuitBreakerPolicy
+
+// DefaultCircuitBreakerPolicy returns the circuit breaker policy used for
+// zero fields: open after 5 consecutive failures, probe again after 30 seconds
+// with one request at a time.
+func DefaultCircuitBreakerPolicy() CircuitBreakerPolicy {
+	return request.DefaultCircuitBreakerPolicy()
+}
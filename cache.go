@@ -0,0 +1,56 @@
+package blindpay
+
+import (
+	"context"
+	"time"
+
+	"github.com/blindpaylabs/blindpay-go/internal/request"
+)
+
+// CachePolicy controls which operations are cached and for how long.
+// It is an alias to the internal request.CachePolicy for convenience.
+type CachePolicy = request.CachePolicy
+
+// CacheStore stores cached response bodies.
+// It is an alias to the internal request.CacheStore for convenience.
+type CacheStore = request.CacheStore
+
+// MemoryCacheStore is a CacheStore that keeps values in memory.
+// It is an alias to the internal request.MemoryCacheStore for convenience.
+type MemoryCacheStore = request.MemoryCacheStore
+
+// RedisClient is the subset of a Redis client used by RedisCacheStore.
+// It is an alias to the internal request.RedisClient for convenience.
+type RedisClient = request.RedisClient
+
+// RedisCacheStore is a CacheStore backed by Redis.
+// It is an alias to the internal request.RedisCacheStore for convenience.
+type RedisCacheStore = request.RedisCacheStore
+
+// DefaultCacheTTL returns the cache TTLs used when CachePolicy.TTL is nil: one
+// hour for available.GetRails, GetBankDetails, GetNaicsCodes and
+// GetSwiftCodeBankDetails, and five minutes for fees.Get.
+func DefaultCacheTTL() map[string]time.Duration {
+	return request.DefaultCacheTTL()
+}
+
+// NewMemoryCacheStore creates an empty in-memory cache store.
+func NewMemoryCacheStore() *MemoryCacheStore {
+	return request.NewMemoryCacheStore()
+}
+
+// NewRedisCacheStore creates a cache store that keeps values in Redis under
+// keys prefixed by namespace, which may be empty.
+func NewRedisCacheStore(client RedisClient, namespace string) *RedisCacheStore {
+	return request.NewRedisCacheStore(client, namespace)
+}
+
+// InvalidateCache drops the cached responses of the given operations, such as
+// "available.GetRails", or of every operation when none is given. It does
+// nothing when the client was created without WithCache.
+func (c *Client) InvalidateCache(ctx context.Context, operations ...string) error {
+	if c.cfg.Cache == nil {
+		return nil
+	}
+	return c.cfg.Cache.Invalidate(ctx, operations...)
+}
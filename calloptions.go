@@ -0,0 +1,55 @@
+package blindpay
+
+import (
+	"context"
+	"time"
+
+	"github.com/blindpaylabs/blindpay-go/internal/request"
+)
+
+// CallOption configures a single API call through its context.
+// It is an alias to the internal request.CallOption for convenience.
+type CallOption = request.CallOption
+
+// WithCallOptions returns a context that applies the given options to every
+// API call made with it, on top of the options already carried by ctx:
+//
+//	ctx := blindpay.WithCallOptions(ctx,
+//		blindpay.CallTimeout(2*time.Minute),
+//		blindpay.CallInstanceID("in_000000000001"),
+//	)
+//	payout, err := client.Payouts.CreateEvm(ctx, params)
+func WithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	return request.WithCallOptions(ctx, opts...)
+}
+
+// CallTimeout bounds the whole call, retries included. It replaces the
+// timeout of the client's http.Client for the call.
+func CallTimeout(d time.Duration) CallOption {
+	return request.CallTimeout(d)
+}
+
+// CallHeader adds a header to the call. It cannot replace headers set by the
+// SDK, such as Authorization.
+func CallHeader(key, value string) CallOption {
+	return request.CallHeader(key, value)
+}
+
+// CallIdempotencyKey sends the given Idempotency-Key with the first create or
+// other write call made with the context, when the params of that call carry
+// no IdempotencyKey of their own. Later calls made with the context, and GET
+// and DELETE calls, do not send it, so that two creates never share a key.
+func CallIdempotencyKey(key string) CallOption {
+	return request.CallIdempotencyKey(key)
+}
+
+// CallRetryPolicy replaces the client retry policy for the call.
+func CallRetryPolicy(policy RetryPolicy) CallOption {
+	return request.CallRetryPolicy(policy)
+}
+
+// CallInstanceID makes the call act on the given instance instead of the
+// one the client was created with.
+func CallInstanceID(instanceID string) CallOption {
+	return request.CallInstanceID(instanceID)
+}
@@ -0,0 +1,166 @@
+package blindpay
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blindpaylabs/blindpay-go/internal/request"
+)
+
+// Environment is the BlindPay environment a client is meant to run against.
+// It is an alias to the internal request.Environment for convenience.
+type Environment = request.Environment
+
+const (
+	// EnvironmentSandbox rejects mainnet networks such as NetworkBase.
+	EnvironmentSandbox = request.EnvironmentSandbox
+	// EnvironmentProduction rejects testnet networks such as
+	// NetworkBaseSepolia or NetworkSolanaDevnet.
+	EnvironmentProduction = request.EnvironmentProduction
+)
+
+// Environment variables read by ConfigFromEnv and, as keys, by ConfigFromFile.
+const (
+	EnvAPIKey      = "BLINDPAY_API_KEY"
+	EnvInstanceID  = "BLINDPAY_INSTANCE_ID"
+	EnvBaseURL     = "BLINDPAY_BASE_URL"
+	EnvEnvironment = "BLINDPAY_ENVIRONMENT"
+	EnvTimeout     = "BLINDPAY_TIMEOUT"
+	EnvMaxAttempts = "BLINDPAY_MAX_ATTEMPTS"
+)
+
+// Config holds the settings needed to create a client, as loaded by
+// ConfigFromEnv or ConfigFromFile. Zero fields keep the client defaults.
+type Config struct {
+	APIKey     string
+	InstanceID string
+	BaseURL    string
+	// Environment, when set, makes the client reject networks that do not
+	// belong to it. See WithEnvironment.
+	Environment Environment
+	// Timeout replaces the timeout of the default http.Client.
+	Timeout time.Duration
+	// MaxAttempts, when greater than 1, enables retries with
+	// DefaultRetryPolicy allowing this many attempts.
+	MaxAttempts int
+}
+
+// ConfigFromEnv loads a Config from the BLINDPAY_* environment variables.
+//
+// BLINDPAY_TIMEOUT is a Go duration such as "45s", BLINDPAY_MAX_ATTEMPTS a
+// positive integer and BLINDPAY_ENVIRONMENT either "sandbox" or "production".
+func ConfigFromEnv() (Config, error) {
+	return parseConfig(os.Getenv)
+}
+
+// ConfigFromFile loads a Config from a file of KEY=VALUE lines using the same
+// keys as ConfigFromEnv, such as a .env file. Blank lines and lines starting
+// with # are ignored, and values may be wrapped in single or double quotes.
+// The process environment is not consulted.
+func ConfigFromFile(path string) (Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(text, "export "), "=")
+		if !ok {
+			return Config{}, fmt.Errorf("%s:%d: expected KEY=VALUE", path, line)
+		}
+		values[strings.TrimSpace(key)] = unquote(strings.TrimSpace(value))
+	}
+	if err := scanner.Err(); err != nil {
+		return Config{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	return parseConfig(func(key string) string { return values[key] })
+}
+
+// NewFromEnv creates a client configured by the BLINDPAY_* environment
+// variables. See ConfigFromEnv. Options are applied after the configuration.
+func NewFromEnv(opts ...Option) (*Client, error) {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return NewFromConfig(cfg, opts...)
+}
+
+// NewFromConfig creates a client from cfg. Options are applied after the
+// configuration, so they take precedence over it.
+func NewFromConfig(cfg Config, opts ...Option) (*Client, error) {
+	var configured []Option
+	if cfg.BaseURL != "" {
+		configured = append(configured, WithBaseURL(cfg.BaseURL))
+	}
+	if cfg.Environment != "" {
+		configured = append(configured, WithEnvironment(cfg.Environment))
+	}
+	if cfg.Timeout > 0 {
+		configured = append(configured, WithHTTPClient(&http.Client{Timeout: cfg.Timeout}))
+	}
+	if cfg.MaxAttempts > 0 {
+		policy := DefaultRetryPolicy()
+		policy.MaxAttempts = cfg.MaxAttempts
+		configured = append(configured, WithRetryPolicy(policy))
+	}
+
+	return New(cfg.APIKey, cfg.InstanceID, append(configured, opts...)...)
+}
+
+// parseConfig builds a Config from the values returned by lookup.
+func parseConfig(lookup func(key string) string) (Config, error) {
+	cfg := Config{
+		APIKey:     lookup(EnvAPIKey),
+		InstanceID: lookup(EnvInstanceID),
+		BaseURL:    lookup(EnvBaseURL),
+	}
+
+	env, err := request.ParseEnvironment(lookup(EnvEnvironment))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid %s: %w", EnvEnvironment, err)
+	}
+	cfg.Environment = env
+
+	if v := lookup(EnvTimeout); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil || timeout <= 0 {
+			return Config{}, fmt.Errorf("invalid %s %q: expected a positive duration such as 30s", EnvTimeout, v)
+		}
+		cfg.Timeout = timeout
+	}
+
+	if v := lookup(EnvMaxAttempts); v != "" {
+		attempts, err := strconv.Atoi(v)
+		if err != nil || attempts < 1 {
+			return Config{}, fmt.Errorf("invalid %s %q: expected a positive integer", EnvMaxAttempts, v)
+		}
+		cfg.MaxAttempts = attempts
+	}
+
+	return cfg, nil
+}
+
+// unquote strips one pair of matching single or double quotes around value.
+func unquote(value string) string {
+	if len(value) >= 2 {
+		if q := value[0]; (q == '"' || q == '\'') && value[len(value)-1] == q {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
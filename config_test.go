@@ -0,0 +1,72 @@
+package blindpay
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvAPIKey, "test_key")
+	t.Setenv(EnvInstanceID, "in_000000000000")
+	t.Setenv(EnvBaseURL, "https://example.com/v1")
+	t.Setenv(EnvEnvironment, "sandbox")
+	t.Setenv(EnvTimeout, "45s")
+	t.Setenv(EnvMaxAttempts, "5")
+
+	cfg, err := ConfigFromEnv()
+	require.NoError(t, err)
+	require.Equal(t, Config{
+		APIKey:      "test_key",
+		InstanceID:  "in_000000000000",
+		BaseURL:     "https://example.com/v1",
+		Environment: EnvironmentSandbox,
+		Timeout:     45 * time.Second,
+		MaxAttempts: 5,
+	}, cfg)
+
+	client, err := NewFromEnv()
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/v1", client.cfg.BaseURL)
+	require.Equal(t, 45*time.Second, client.cfg.HTTPClient.Timeout)
+	require.Equal(t, 5, client.cfg.Retry.MaxAttempts)
+	require.Equal(t, EnvironmentSandbox, client.environment)
+}
+
+func TestConfigFromEnv_Invalid(t *testing.T) {
+	t.Setenv(EnvTimeout, "soon")
+
+	_, err := ConfigFromEnv()
+	require.ErrorContains(t, err, EnvTimeout)
+}
+
+func TestConfigFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "# BlindPay\n" +
+		"BLINDPAY_API_KEY=\"test_key\"\n" +
+		"export BLINDPAY_INSTANCE_ID='in_000000000000'\n" +
+		"\n" +
+		"BLINDPAY_ENVIRONMENT=production\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	cfg, err := ConfigFromFile(path)
+	require.NoError(t, err)
+	require.Equal(t, Config{
+		APIKey:      "test_key",
+		InstanceID:  "in_000000000000",
+		Environment: EnvironmentProduction,
+	}, cfg)
+
+	// Retries are opt-in.
+	client, err := NewFromConfig(cfg)
+	require.NoError(t, err)
+	require.Nil(t, client.cfg.Retry)
+}
+
+func TestNew_UnknownEnvironment(t *testing.T) {
+	_, err := New("test_key", "in_000000000000", WithEnvironment("staging"))
+	require.Error(t, err)
+}
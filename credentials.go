@@ -0,0 +1,38 @@
+package blindpay
+
+import (
+	"time"
+
+	"github.com/blindpaylabs/blindpay-go/internal/request"
+)
+
+// CredentialProvider supplies the API key used to authenticate each request.
+// It is an alias to the internal request.CredentialProvider for convenience.
+//
+// The provider is consulted for every request. When the API answers 401, the
+// client calls Refresh once and, if it returns a different key, sends the
+// request again. This lets a rotation job create a new key with
+// Client.APIKeys.Create, publish it where the provider reads it, and delete
+// the old key once every service has picked up the new one.
+type CredentialProvider = request.CredentialProvider
+
+// StaticCredentials is a CredentialProvider that always returns the same key.
+// It is an alias to the internal request.StaticCredentials for convenience.
+type StaticCredentials = request.StaticCredentials
+
+// CredentialFunc is a CredentialProvider that calls the function for every
+// request, and once more on refresh.
+// It is an alias to the internal request.CredentialFunc for convenience.
+type CredentialFunc = request.CredentialFunc
+
+// FileCredentials is a CredentialProvider that reads the API key from a file
+// and reloads it when the file changes.
+// It is an alias to the internal request.FileCredentials for convenience.
+type FileCredentials = request.FileCredentials
+
+// NewFileCredentials returns a provider reading the key from path, such as a
+// mounted secret. The file is checked for changes at most once per interval,
+// and on every refresh.
+func NewFileCredentials(path string, interval time.Duration) *FileCredentials {
+	return request.NewFileCredentials(path, interval)
+}
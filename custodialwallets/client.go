@@ -60,33 +60,35 @@ func NewClient(cfg *config.Config) *Client {
 // List retrieves all custodial wallets for a receiver.
 func (c *Client) List(ctx context.Context, receiverID string) ([]CustodialWallet, error) {
 	if receiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/wallets", c.instanceID, receiverID)
-	return request.Do[[]CustodialWallet](c.cfg, ctx, "GET", path, nil)
+	return request.Do[[]CustodialWallet](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("custodialwallets.List", "/instances/{instance_id}/receivers/{receiver_id}/wallets"))
 }
 
 // Get retrieves a specific custodial wallet.
 func (c *Client) Get(ctx context.Context, receiverID, id string) (*CustodialWallet, error) {
 	if receiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 	if id == "" {
-		return nil, fmt.Errorf("id cannot be empty")
+		return nil, request.EmptyParam("id")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/wallets/%s", c.instanceID, receiverID, id)
-	return request.Do[*CustodialWallet](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*CustodialWallet](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("custodialwallets.Get", "/instances/{instance_id}/receivers/{receiver_id}/wallets/{id}"))
 }
 
 // Create creates a new custodial wallet.
 func (c *Client) Create(ctx context.Context, params *CreateParams) (*CustodialWallet, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/wallets", c.instanceID, params.ReceiverID)
@@ -100,32 +102,36 @@ func (c *Client) Create(ctx context.Context, params *CreateParams) (*CustodialWa
 		body["external_id"] = *params.ExternalID
 	}
 
-	return request.Do[*CustodialWallet](c.cfg, ctx, "POST", path, body)
+	return request.Do[*CustodialWallet](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("custodialwallets.Create", "/instances/{instance_id}/receivers/{receiver_id}/wallets"),
+		request.WithParams(params))
 }
 
 // GetBalance retrieves the balance of a custodial wallet.
 func (c *Client) GetBalance(ctx context.Context, receiverID, id string) (*GetBalanceResponse, error) {
 	if receiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 	if id == "" {
-		return nil, fmt.Errorf("id cannot be empty")
+		return nil, request.EmptyParam("id")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/wallets/%s/balance", c.instanceID, receiverID, id)
-	return request.Do[*GetBalanceResponse](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*GetBalanceResponse](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("custodialwallets.GetBalance", "/instances/{instance_id}/receivers/{receiver_id}/wallets/{id}/balance"))
 }
 
 // Delete deletes a custodial wallet.
 func (c *Client) Delete(ctx context.Context, receiverID, id string) error {
 	if receiverID == "" {
-		return fmt.Errorf("receiver ID cannot be empty")
+		return request.EmptyParam("receiver ID")
 	}
 	if id == "" {
-		return fmt.Errorf("id cannot be empty")
+		return request.EmptyParam("id")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/wallets/%s", c.instanceID, receiverID, id)
-	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil)
+	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil,
+		request.WithOperation("custodialwallets.Delete", "/instances/{instance_id}/receivers/{receiver_id}/wallets/{id}"))
 	return err
 }
@@ -11,3 +11,62 @@ type APIError = request.APIError
 // ErrorItem represents an individual error in the errors array.
 // It is an alias to the internal request.ErrorItem for convenience.
 type ErrorItem = request.ErrorItem
+
+// ErrorCode is the machine-readable code of an ErrorItem.
+// It is an alias to the internal request.ErrorCode for convenience.
+type ErrorCode = request.ErrorCode
+
+// Error codes returned by the BlindPay API in ErrorItem.Code.
+const (
+	ErrorCodeNotFound            = request.ErrorCodeNotFound
+	ErrorCodeUnauthorized        = request.ErrorCodeUnauthorized
+	ErrorCodeForbidden           = request.ErrorCodeForbidden
+	ErrorCodeRateLimited         = request.ErrorCodeRateLimited
+	ErrorCodeValidation          = request.ErrorCodeValidation
+	ErrorCodeConflict            = request.ErrorCodeConflict
+	ErrorCodeQuoteExpired        = request.ErrorCodeQuoteExpired
+	ErrorCodeInsufficientBalance = request.ErrorCodeInsufficientBalance
+)
+
+// Sentinel errors matched by errors.Is against an *APIError:
+//
+//	if errors.Is(err, blindpay.ErrNotFound) {
+//		// ...
+//	}
+var (
+	ErrNotFound            = request.ErrNotFound
+	ErrUnauthorized        = request.ErrUnauthorized
+	ErrForbidden           = request.ErrForbidden
+	ErrRateLimited         = request.ErrRateLimited
+	ErrValidation          = request.ErrValidation
+	ErrConflict            = request.ErrConflict
+	ErrQuoteExpired        = request.ErrQuoteExpired
+	ErrInsufficientBalance = request.ErrInsufficientBalance
+)
+
+// ErrInvalidParams is matched by errors.Is when the SDK rejects a call before
+// sending it, for example because a required ID is empty.
+var ErrInvalidParams = request.ErrInvalidParams
+
+// ErrTransport is matched by errors.Is when a request could not be sent or
+// its response could not be read.
+var ErrTransport = request.ErrTransport
+
+// ParamError reports a call rejected before it was sent because a parameter is missing or invalid.
+// It is an alias to the internal request.ParamError for convenience.
+type ParamError = request.ParamError
+
+// TransportError reports a request that could not be sent or whose response could not be read.
+// It is an alias to the internal request.TransportError for convenience.
+type TransportError = request.TransportError
+
+// CreateError reports a failed create call together with the Idempotency-Key it sent:
+//
+//	payout, err := client.Payouts.CreateEvm(ctx, params)
+//	var createErr *blindpay.CreateError
+//	if errors.As(err, &createErr) {
+//		params.IdempotencyKey = createErr.IdempotencyKey // retry safely
+//	}
+//
+// It is an alias to the internal request.CreateError for convenience.
+type CreateError = request.CreateError
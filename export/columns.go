@@ -0,0 +1,214 @@
+package export
+
+import (
+	"time"
+
+	"github.com/blindpaylabs/blindpay-go/internal/types"
+	"github.com/blindpaylabs/blindpay-go/payins"
+	"github.com/blindpaylabs/blindpay-go/payouts"
+	"github.com/blindpaylabs/blindpay-go/transfers"
+)
+
+// column is one field of the flattened schema of T. Values are strings,
+// float64, bool, time.Time or nil.
+type column[T any] struct {
+	name  string
+	value func(T) any
+}
+
+// columnNames returns the names of columns in order.
+func columnNames[T any](columns []column[T]) []string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.name
+	}
+	return names
+}
+
+// PayoutColumns returns the columns of a payout export, in order.
+func PayoutColumns() []string {
+	return columnNames(payoutColumns)
+}
+
+// PayinColumns returns the columns of a payin export, in order.
+func PayinColumns() []string {
+	return columnNames(payinColumns)
+}
+
+// TransferColumns returns the columns of a transfer export, in order.
+func TransferColumns() []string {
+	return columnNames(transferColumns)
+}
+
+var payoutColumns = flatten(
+	[]column[payouts.Payout]{
+		{"id", func(p payouts.Payout) any { return p.ID }},
+		{"status", func(p payouts.Payout) any { return string(p.Status) }},
+		{"created_at", func(p payouts.Payout) any { return p.CreatedAt }},
+		{"updated_at", func(p payouts.Payout) any { return p.UpdatedAt }},
+		{"instance_id", func(p payouts.Payout) any { return p.InstanceID }},
+		{"receiver_id", func(p payouts.Payout) any { return p.ReceiverID }},
+		{"quote_id", func(p payouts.Payout) any { return p.QuoteID }},
+		{"rail", func(p payouts.Payout) any { return string(p.Type) }},
+		{"bank_account_name", func(p payouts.Payout) any { return p.Name }},
+		{"network", func(p payouts.Payout) any { return string(p.Network) }},
+		{"token", func(p payouts.Payout) any { return string(p.Token) }},
+		{"currency", func(p payouts.Payout) any { return string(p.Currency) }},
+		{"sender_wallet_address", func(p payouts.Payout) any { return p.SenderWalletAddress }},
+		{"sender_amount", func(p payouts.Payout) any { return p.SenderAmount }},
+		{"receiver_amount", func(p payouts.Payout) any { return p.ReceiverAmount }},
+		{"receiver_local_amount", func(p payouts.Payout) any { return p.ReceiverLocalAmount }},
+		{"partner_fee_amount", func(p payouts.Payout) any { return p.PartnerFeeAmount }},
+		{"total_fee_amount", func(p payouts.Payout) any { return p.TotalFeeAmount }},
+		{"commercial_quotation", func(p payouts.Payout) any { return p.CommercialQuotation }},
+		{"blindpay_quotation", func(p payouts.Payout) any { return p.BlindpayQuotation }},
+		{"description", func(p payouts.Payout) any { return p.Description }},
+	},
+	prefixed("tracking_transaction_", trackingTransactionColumns, func(p payouts.Payout) *types.TrackingTransaction { return p.TrackingTransaction }),
+	prefixed("tracking_payment_", trackingPaymentColumns, func(p payouts.Payout) *types.TrackingPayment { return p.TrackingPayment }),
+	prefixed("tracking_complete_", trackingCompleteColumns, func(p payouts.Payout) *types.TrackingComplete { return p.TrackingComplete }),
+	prefixed("tracking_partner_fee_", trackingPartnerFeeColumns, func(p payouts.Payout) *types.TrackingPartnerFee { return p.TrackingPartnerFee }),
+)
+
+var payinColumns = flatten(
+	[]column[payins.Payin]{
+		{"id", func(p payins.Payin) any { return p.ID }},
+		{"status", func(p payins.Payin) any { return string(p.Status) }},
+		{"created_at", func(p payins.Payin) any { return p.CreatedAt }},
+		{"updated_at", func(p payins.Payin) any { return p.UpdatedAt }},
+		{"instance_id", func(p payins.Payin) any { return p.InstanceID }},
+		{"receiver_id", func(p payins.Payin) any { return p.ReceiverID }},
+		{"payin_quote_id", func(p payins.Payin) any { return p.PayinQuoteID }},
+		{"payment_method", func(p payins.Payin) any { return p.PaymentMethod }},
+		{"network", func(p payins.Payin) any { return string(p.Network) }},
+		{"token", func(p payins.Payin) any { return string(p.Token) }},
+		{"currency", func(p payins.Payin) any { return p.Currency }},
+		{"sender_amount", func(p payins.Payin) any { return p.SenderAmount }},
+		{"receiver_amount", func(p payins.Payin) any { return p.ReceiverAmount }},
+		{"partner_fee_amount", func(p payins.Payin) any { return p.PartnerFeeAmount }},
+		{"total_fee_amount", func(p payins.Payin) any { return p.TotalFeeAmount }},
+		{"billing_fee", func(p payins.Payin) any { return p.BillingFee }},
+		{"commercial_quotation", func(p payins.Payin) any { return p.CommercialQuotation }},
+		{"blindpay_quotation", func(p payins.Payin) any { return p.BlindpayQuotation }},
+	},
+	prefixed("tracking_transaction_", trackingTransactionColumns, func(p payins.Payin) *types.TrackingTransaction { return p.TrackingTransaction }),
+	prefixed("tracking_payment_", trackingPaymentColumns, func(p payins.Payin) *types.TrackingPayment { return p.TrackingPayment }),
+	prefixed("tracking_complete_", trackingCompleteColumns, func(p payins.Payin) *types.TrackingComplete { return p.TrackingComplete }),
+	prefixed("tracking_partner_fee_", trackingPartnerFeeColumns, func(p payins.Payin) *types.TrackingPartnerFee { return p.TrackingPartnerFee }),
+)
+
+var transferColumns = flatten(
+	[]column[transfers.Transfer]{
+		{"id", func(t transfers.Transfer) any { return t.ID }},
+		{"status", func(t transfers.Transfer) any { return string(t.Status) }},
+		{"created_at", func(t transfers.Transfer) any { return t.CreatedAt }},
+		{"updated_at", func(t transfers.Transfer) any { return t.UpdatedAt }},
+		{"instance_id", func(t transfers.Transfer) any { return t.InstanceID }},
+		{"receiver_id", func(t transfers.Transfer) any { return t.ReceiverID }},
+		{"transfer_quote_id", func(t transfers.Transfer) any { return t.TransferQuoteID }},
+		{"wallet_id", func(t transfers.Transfer) any { return t.WalletID }},
+		{"external_id", func(t transfers.Transfer) any { return stringValue(t.ExternalID) }},
+		{"network", func(t transfers.Transfer) any { return string(t.Network) }},
+		{"sender_token", func(t transfers.Transfer) any { return string(t.SenderToken) }},
+		{"sender_amount", func(t transfers.Transfer) any { return t.SenderAmount }},
+		{"receiver_network", func(t transfers.Transfer) any { return string(t.ReceiverNetwork) }},
+		{"receiver_token", func(t transfers.Transfer) any { return string(t.ReceiverToken) }},
+		{"receiver_amount", func(t transfers.Transfer) any { return t.ReceiverAmount }},
+		{"receiver_wallet_address", func(t transfers.Transfer) any { return t.ReceiverWalletAddress }},
+		{"partner_fee_amount", func(t transfers.Transfer) any { return floatValue(t.PartnerFeeAmount) }},
+		{"tracking_transaction_monitoring_step", func(t transfers.Transfer) any { return t.TrackingTransactionMonitoring.Step }},
+		{"tracking_transaction_monitoring_risk_score", func(t transfers.Transfer) any { return floatValue(t.TrackingTransactionMonitoring.RiskScore) }},
+		{"tracking_transaction_monitoring_completed_at", func(t transfers.Transfer) any { return stringValue(t.TrackingTransactionMonitoring.CompletedAt) }},
+	},
+	prefixed("tracking_paymaster_", transferStepColumns, func(t transfers.Transfer) *transfers.TrackingStep { return &t.TrackingPaymaster }),
+	prefixed("tracking_bridge_swap_", transferStepColumns, func(t transfers.Transfer) *transfers.TrackingStep { return &t.TrackingBridgeSwap }),
+	prefixed("tracking_complete_", transferStepColumns, func(t transfers.Transfer) *transfers.TrackingStep { return &t.TrackingComplete }),
+	prefixed("tracking_partner_fee_", transferStepColumns, func(t transfers.Transfer) *transfers.TrackingStep { return &t.TrackingPartnerFee }),
+)
+
+var trackingTransactionColumns = []column[*types.TrackingTransaction]{
+	{"step", func(t *types.TrackingTransaction) any { return t.Step }},
+	{"status", func(t *types.TrackingTransaction) any { return t.Status }},
+	{"transaction_hash", func(t *types.TrackingTransaction) any { return t.TransactionHash }},
+	{"external_id", func(t *types.TrackingTransaction) any { return t.ExternalID }},
+	{"completed_at", func(t *types.TrackingTransaction) any { return t.CompletedAt }},
+}
+
+var trackingPaymentColumns = []column[*types.TrackingPayment]{
+	{"step", func(t *types.TrackingPayment) any { return t.Step }},
+	{"provider_name", func(t *types.TrackingPayment) any { return t.ProviderName }},
+	{"provider_transaction_id", func(t *types.TrackingPayment) any { return t.ProviderTransactionID }},
+	{"provider_status", func(t *types.TrackingPayment) any { return t.ProviderStatus }},
+	{"estimated_time_of_arrival", func(t *types.TrackingPayment) any { return t.EstimatedTimeOfArrival }},
+	{"completed_at", func(t *types.TrackingPayment) any { return t.CompletedAt }},
+}
+
+var trackingCompleteColumns = []column[*types.TrackingComplete]{
+	{"step", func(t *types.TrackingComplete) any { return t.Step }},
+	{"status", func(t *types.TrackingComplete) any { return t.Status }},
+	{"transaction_hash", func(t *types.TrackingComplete) any { return t.TransactionHash }},
+	{"completed_at", func(t *types.TrackingComplete) any { return t.CompletedAt }},
+}
+
+var trackingPartnerFeeColumns = []column[*types.TrackingPartnerFee]{
+	{"step", func(t *types.TrackingPartnerFee) any { return t.Step }},
+	{"transaction_hash", func(t *types.TrackingPartnerFee) any { return t.TransactionHash }},
+	{"completed_at", func(t *types.TrackingPartnerFee) any { return t.CompletedAt }},
+}
+
+var transferStepColumns = []column[*transfers.TrackingStep]{
+	{"step", func(t *transfers.TrackingStep) any { return t.Step }},
+	{"transaction_hash", func(t *transfers.TrackingStep) any { return stringValue(t.TransactionHash) }},
+	{"gas_fee", func(t *transfers.TrackingStep) any { return stringValue(t.GasFee) }},
+	{"completed_at", func(t *transfers.TrackingStep) any { return stringValue(t.CompletedAt) }},
+	{"error_message", func(t *transfers.TrackingStep) any { return stringValue(t.ErrorMessage) }},
+}
+
+// prefixed lifts the columns of a nested step into columns of T named with
+// prefix. They are empty when the step is nil.
+func prefixed[T, S any](prefix string, columns []column[*S], step func(T) *S) []column[T] {
+	lifted := make([]column[T], len(columns))
+	for i, col := range columns {
+		value := col.value
+		lifted[i] = column[T]{
+			name: prefix + col.name,
+			value: func(record T) any {
+				s := step(record)
+				if s == nil {
+					return nil
+				}
+				v := value(s)
+				if t, ok := v.(time.Time); ok && t.IsZero() {
+					return nil
+				}
+				return v
+			},
+		}
+	}
+	return lifted
+}
+
+// flatten concatenates column groups.
+func flatten[T any](groups ...[]column[T]) []column[T] {
+	var columns []column[T]
+	for _, group := range groups {
+		columns = append(columns, group...)
+	}
+	return columns
+}
+
+// stringValue returns the value of s, or nil.
+func stringValue(s *string) any {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+// floatValue returns the value of f, or nil.
+func floatValue(f *float64) any {
+	if f == nil {
+		return nil
+	}
+	return *f
+}
@@ -0,0 +1,507 @@
+// Package export writes payouts, payins and transfers to CSV or JSONL files
+// with a stable column schema, for spreadsheets and reporting jobs.
+//
+// Records are fetched in offset chunks and written as they arrive. When
+// Options.Checkpoint names a file, the position of the export is saved there
+// after every chunk, so an export interrupted by a crash continues where it
+// stopped when run again with the same options. The checkpoint also records
+// how many bytes of output were committed; when the output is a file, the
+// part of the chunk written before the crash, including a torn last line, is
+// cut off before the export continues:
+//
+//	opts := export.Options{
+//		Format:     export.FormatCSV,
+//		Statuses:   []types.TransactionStatus{types.TransactionStatusCompleted},
+//		From:       time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+//		Checkpoint: "payouts.csv.checkpoint",
+//	}
+//
+//	f, err := export.OpenFile("payouts.csv", opts)
+//	if err != nil {
+//		return err
+//	}
+//	defer f.Close()
+//
+//	result, err := export.Payouts(ctx, client.Payouts, f, opts)
+//
+// The columns of each format are listed by PayoutColumns, PayinColumns and
+// TransferColumns. Nested tracking steps are flattened into columns prefixed
+// with the name of the step, such as tracking_payment_provider_status.
+// Sensitive fields such as bank account numbers and tax IDs are never
+// exported.
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blindpaylabs/blindpay-go/internal/types"
+	"github.com/blindpaylabs/blindpay-go/payins"
+	"github.com/blindpaylabs/blindpay-go/payouts"
+	"github.com/blindpaylabs/blindpay-go/transfers"
+)
+
+// Format is the file format of an export.
+type Format string
+
+const (
+	// FormatCSV writes a header line followed by one comma-separated line per record.
+	FormatCSV Format = "csv"
+	// FormatJSONL writes one JSON object per line, with the column names as keys.
+	FormatJSONL Format = "jsonl"
+)
+
+// DefaultChunkSize is the number of records fetched per request when
+// Options.ChunkSize is zero.
+const DefaultChunkSize = 500
+
+// Options configures an export.
+type Options struct {
+	// Format is the output format. It defaults to FormatCSV.
+	Format Format
+	// Statuses keeps only the records with one of the given statuses. All
+	// records are kept when it is empty.
+	Statuses []types.TransactionStatus
+	// From keeps only the records created at or after it, when set.
+	From time.Time
+	// To keeps only the records created before it, when set.
+	To time.Time
+	// ChunkSize is the number of records fetched per request.
+	ChunkSize int
+	// Checkpoint is the path of the file recording the progress of the
+	// export. When set, an unfinished export recorded there is resumed, and
+	// the file is removed once the export completes.
+	Checkpoint string
+}
+
+// Result summarizes a completed export.
+type Result struct {
+	// Fetched is the number of records read from the API, including those
+	// read before a resume.
+	Fetched int
+	// Written is the number of records written, including those written
+	// before a resume.
+	Written int
+	// Resumed reports whether the export continued from a checkpoint.
+	Resumed bool
+}
+
+// checkpoint is the progress of an export, saved after every chunk.
+type checkpoint struct {
+	Dataset string `json:"dataset"`
+	Format  Format `json:"format"`
+	Filter  string `json:"filter"`
+	Offset  int    `json:"offset"`
+	Written int    `json:"written"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// source describes how to fetch and flatten the records of a dataset.
+type source[T any] struct {
+	name      string
+	columns   []column[T]
+	fetch     func(ctx context.Context, offset, limit int) (records []T, hasMore bool, err error)
+	status    func(T) types.TransactionStatus
+	createdAt func(T) time.Time
+}
+
+// Payouts exports the payouts returned by client.Export to w.
+func Payouts(ctx context.Context, client *payouts.Client, w io.Writer, opts Options) (*Result, error) {
+	return run(ctx, w, opts, source[payouts.Payout]{
+		name:    "payouts",
+		columns: payoutColumns,
+		fetch: func(ctx context.Context, offset, limit int) ([]payouts.Payout, bool, error) {
+			records, err := client.Export(ctx, &payouts.ExportParams{Limit: limit, Offset: offset})
+			return records, len(records) == limit, err
+		},
+		status:    func(p payouts.Payout) types.TransactionStatus { return p.Status },
+		createdAt: func(p payouts.Payout) time.Time { return p.CreatedAt },
+	})
+}
+
+// Payins exports the payins returned by client.Export to w. A single status
+// filter is applied by the API, other filters by the SDK.
+func Payins(ctx context.Context, client *payins.Client, w io.Writer, opts Options) (*Result, error) {
+	var status types.TransactionStatus
+	if len(opts.Statuses) == 1 {
+		status = opts.Statuses[0]
+	}
+
+	return run(ctx, w, opts, source[payins.Payin]{
+		name:    "payins",
+		columns: payinColumns,
+		fetch: func(ctx context.Context, offset, limit int) ([]payins.Payin, bool, error) {
+			records, err := client.Export(ctx, status, limit, offset)
+			return records, len(records) == limit, err
+		},
+		status:    func(p payins.Payin) types.TransactionStatus { return p.Status },
+		createdAt: func(p payins.Payin) time.Time { return p.CreatedAt },
+	})
+}
+
+// Transfers exports the transfers returned by client.List to w.
+func Transfers(ctx context.Context, client *transfers.Client, w io.Writer, opts Options) (*Result, error) {
+	return run(ctx, w, opts, source[transfers.Transfer]{
+		name:    "transfers",
+		columns: transferColumns,
+		fetch: func(ctx context.Context, offset, limit int) ([]transfers.Transfer, bool, error) {
+			resp, err := client.List(ctx, &transfers.ListParams{Limit: limit, Offset: offset})
+			if err != nil || resp == nil {
+				return nil, false, err
+			}
+			return resp.Data, resp.Pagination.HasMore, nil
+		},
+		status:    func(t transfers.Transfer) types.TransactionStatus { return t.Status },
+		createdAt: func(t transfers.Transfer) time.Time { return t.CreatedAt },
+	})
+}
+
+// OpenFile opens the output file of an export: as is when opts.Checkpoint
+// records an unfinished export, which then cuts the file back to the output
+// it committed, and truncated otherwise.
+func OpenFile(path string, opts Options) (*os.File, error) {
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if opts.Checkpoint != "" {
+		if _, err := os.Stat(opts.Checkpoint); err == nil {
+			flag = os.O_CREATE | os.O_WRONLY
+		}
+	}
+	return os.OpenFile(path, flag, 0o644)
+}
+
+// truncater is implemented by outputs, such as *os.File, that a resumed
+// export can cut back to the output committed by its checkpoint.
+type truncater interface {
+	io.Seeker
+	Truncate(size int64) error
+}
+
+// rewind cuts w back to the committed size of a resumed export and positions
+// it there, dropping the part of a chunk written before a crash. Outputs that
+// cannot be truncated are written from their current position.
+func rewind(w io.Writer, size int64) error {
+	t, ok := w.(truncater)
+	if !ok {
+		return nil
+	}
+
+	end, err := t.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("export: failed to seek output: %w", err)
+	}
+	if end < size {
+		return fmt.Errorf("export: output has %d bytes, fewer than the %d recorded by the checkpoint", end, size)
+	}
+	if err := t.Truncate(size); err != nil {
+		return fmt.Errorf("export: failed to truncate output: %w", err)
+	}
+	if _, err := t.Seek(size, io.SeekStart); err != nil {
+		return fmt.Errorf("export: failed to seek output: %w", err)
+	}
+	return nil
+}
+
+// run performs an export of src to w.
+func run[T any](ctx context.Context, w io.Writer, opts Options, src source[T]) (*Result, error) {
+	if w == nil {
+		return nil, errors.New("export: writer cannot be nil")
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = FormatCSV
+	}
+	if format != FormatCSV && format != FormatJSONL {
+		return nil, fmt.Errorf("export: unknown format %q", format)
+	}
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	cp := checkpoint{Dataset: src.name, Format: format, Filter: opts.filter()}
+	result := &Result{}
+	if opts.Checkpoint != "" {
+		saved, ok, err := loadCheckpoint(opts.Checkpoint)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			if saved.Dataset != cp.Dataset || saved.Format != cp.Format || saved.Filter != cp.Filter {
+				return nil, fmt.Errorf("export: checkpoint %s belongs to another export (%s, %s)", opts.Checkpoint, saved.Dataset, saved.Format)
+			}
+			cp = saved
+			result.Resumed = true
+		}
+	}
+	result.Fetched = cp.Offset
+	result.Written = cp.Written
+
+	if result.Resumed {
+		if err := rewind(w, cp.Bytes); err != nil {
+			return nil, err
+		}
+	}
+
+	out := newRecordWriter(w, format, columnNames(src.columns), cp.Bytes)
+	if !result.Resumed {
+		if err := out.header(); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		records, hasMore, err := src.fetch(ctx, cp.Offset, chunkSize)
+		if err != nil {
+			return result, fmt.Errorf("export: failed to fetch %s at offset %d: %w", src.name, cp.Offset, err)
+		}
+
+		for _, record := range records {
+			if !opts.keep(src.status(record), src.createdAt(record)) {
+				continue
+			}
+			values := make([]any, len(src.columns))
+			for i, col := range src.columns {
+				values[i] = col.value(record)
+			}
+			if err := out.record(values); err != nil {
+				return result, err
+			}
+			cp.Written++
+		}
+		if err := out.flush(); err != nil {
+			return result, err
+		}
+
+		cp.Offset += len(records)
+		cp.Bytes = out.written()
+		result.Fetched = cp.Offset
+		result.Written = cp.Written
+
+		if !hasMore || len(records) == 0 {
+			break
+		}
+		if opts.Checkpoint != "" {
+			if err := saveCheckpoint(opts.Checkpoint, cp); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	if opts.Checkpoint != "" {
+		if err := os.Remove(opts.Checkpoint); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return result, fmt.Errorf("export: failed to remove checkpoint: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// keep reports whether a record passes the status and date filters.
+func (o Options) keep(status types.TransactionStatus, createdAt time.Time) bool {
+	if len(o.Statuses) > 0 {
+		found := false
+		for _, s := range o.Statuses {
+			if s == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !o.From.IsZero() && createdAt.Before(o.From) {
+		return false
+	}
+	if !o.To.IsZero() && !createdAt.Before(o.To) {
+		return false
+	}
+	return true
+}
+
+// filter returns a fingerprint of the filters, which must not change between
+// an export and its resume.
+func (o Options) filter() string {
+	statuses := make([]string, len(o.Statuses))
+	for i, s := range o.Statuses {
+		statuses[i] = string(s)
+	}
+	return fmt.Sprintf("statuses=%s;from=%s;to=%s", strings.Join(statuses, ","), formatTime(o.From), formatTime(o.To))
+}
+
+// loadCheckpoint reads the checkpoint at path, reporting false when there is none.
+func loadCheckpoint(path string) (checkpoint, bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoint{}, false, nil
+	}
+	if err != nil {
+		return checkpoint{}, false, fmt.Errorf("export: failed to read checkpoint: %w", err)
+	}
+
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return checkpoint{}, false, fmt.Errorf("export: failed to decode checkpoint: %w", err)
+	}
+	return cp, true, nil
+}
+
+// saveCheckpoint atomically replaces the checkpoint at path.
+func saveCheckpoint(path string, cp checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("export: failed to encode checkpoint: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("export: failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("export: failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// recordWriter writes flattened records in one format.
+type recordWriter struct {
+	format  Format
+	columns []string
+	buf     *bufio.Writer
+	csv     *csv.Writer
+	out     *countingWriter
+	w       io.Writer
+}
+
+// newRecordWriter returns a recordWriter for w, which already holds written
+// bytes of output.
+func newRecordWriter(w io.Writer, format Format, columns []string, written int64) *recordWriter {
+	out := &countingWriter{w: w, n: written}
+	rw := &recordWriter{format: format, columns: columns, out: out, w: w}
+	if format == FormatCSV {
+		rw.csv = csv.NewWriter(out)
+	} else {
+		rw.buf = bufio.NewWriter(out)
+	}
+	return rw
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// header writes the CSV header line. JSONL files have no header.
+func (rw *recordWriter) header() error {
+	if rw.csv == nil {
+		return nil
+	}
+	if err := rw.csv.Write(rw.columns); err != nil {
+		return fmt.Errorf("export: failed to write header: %w", err)
+	}
+	return nil
+}
+
+// record writes one record whose values follow the order of the columns.
+func (rw *recordWriter) record(values []any) error {
+	if rw.csv != nil {
+		fields := make([]string, len(values))
+		for i, v := range values {
+			fields[i] = formatValue(v)
+		}
+		if err := rw.csv.Write(fields); err != nil {
+			return fmt.Errorf("export: failed to write record: %w", err)
+		}
+		return nil
+	}
+
+	rw.buf.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			rw.buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(rw.columns[i])
+		rw.buf.Write(key)
+		rw.buf.WriteByte(':')
+
+		if t, ok := v.(time.Time); ok {
+			v = formatTime(t)
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("export: failed to encode %s: %w", rw.columns[i], err)
+		}
+		rw.buf.Write(data)
+	}
+	rw.buf.WriteString("}\n")
+	return nil
+}
+
+// flush writes buffered records to the underlying writer.
+func (rw *recordWriter) flush() error {
+	var err error
+	if rw.csv != nil {
+		rw.csv.Flush()
+		err = rw.csv.Error()
+	} else {
+		err = rw.buf.Flush()
+	}
+	if err != nil {
+		return fmt.Errorf("export: failed to write records: %w", err)
+	}
+
+	if f, ok := rw.w.(*os.File); ok {
+		if err := f.Sync(); err != nil {
+			return fmt.Errorf("export: failed to sync output: %w", err)
+		}
+	}
+	return nil
+}
+
+// written returns the number of bytes of output flushed to the underlying
+// writer, including those written before a resume.
+func (rw *recordWriter) written() int64 {
+	return rw.out.n
+}
+
+// formatValue renders a column value for CSV.
+func formatValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case time.Time:
+		return formatTime(val)
+	}
+	return fmt.Sprint(v)
+}
+
+// formatTime renders a time in UTC as RFC 3339, or as an empty string when zero.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,228 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blindpaylabs/blindpay-go/internal/blindpaytest"
+	"github.com/blindpaylabs/blindpay-go/internal/config"
+	"github.com/blindpaylabs/blindpay-go/internal/types"
+	"github.com/blindpaylabs/blindpay-go/payouts"
+	"github.com/blindpaylabs/blindpay-go/transfers"
+)
+
+func testConfig(transport http.RoundTripper) *config.Config {
+	return &config.Config{
+		BaseURL:    "https://api.blindpay.com",
+		APIKey:     "test_key",
+		InstanceID: "in_000000000000",
+		HTTPClient: &http.Client{Transport: transport},
+		UserAgent:  "test",
+	}
+}
+
+func TestPayouts_CSV(t *testing.T) {
+	transport := &blindpaytest.RoundTripper{
+		T: t,
+		Outs: []json.RawMessage{
+			json.RawMessage(`[
+				{"id":"pa_000000000000","status":"completed","created_at":"2026-01-05T10:00:00Z","sender_amount":1000.5,
+				 "tracking_payment":{"step":"completed","provider_name":"blockchain","provider_status":"paid","completed_at":"2026-01-05T11:00:00Z"}},
+				{"id":"pa_111111111111","status":"failed","created_at":"2026-01-06T10:00:00Z"}
+			]`),
+			json.RawMessage(`[
+				{"id":"pa_222222222222","status":"completed","created_at":"2025-12-31T10:00:00Z"}
+			]`),
+		},
+		Method: http.MethodGet,
+		Path:   "/instances/in_000000000000/export/payouts",
+	}
+
+	var buf bytes.Buffer
+	result, err := Payouts(context.Background(), payouts.NewClient(testConfig(transport)), &buf, Options{
+		Statuses:  []types.TransactionStatus{types.TransactionStatusCompleted},
+		From:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		ChunkSize: 2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, &Result{Fetched: 3, Written: 1}, result)
+	require.Equal(t, "limit=2&offset=2", transport.Requests[1].URL.RawQuery)
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, PayoutColumns(), rows[0])
+
+	row := make(map[string]string)
+	for i, name := range rows[0] {
+		row[name] = rows[1][i]
+	}
+	require.Equal(t, "pa_000000000000", row["id"])
+	require.Equal(t, "1000.5", row["sender_amount"])
+	require.Equal(t, "2026-01-05T10:00:00Z", row["created_at"])
+	require.Equal(t, "paid", row["tracking_payment_provider_status"])
+	require.Equal(t, "2026-01-05T11:00:00Z", row["tracking_payment_completed_at"])
+	require.Equal(t, "", row["tracking_complete_step"])
+}
+
+func TestTransfers_JSONL(t *testing.T) {
+	transport := &blindpaytest.RoundTripper{
+		T: t,
+		Out: json.RawMessage(`{"data":[
+			{"id":"tr_000000000000","status":"completed","sender_amount":10,"tracking_complete":{"step":"completed","transaction_hash":"0xabc"}}
+		],"pagination":{"has_more":false}}`),
+	}
+
+	var buf bytes.Buffer
+	result, err := Transfers(context.Background(), transfers.NewClient(testConfig(transport)), &buf, Options{Format: FormatJSONL})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Written)
+
+	line := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(line, &record))
+	require.Equal(t, "tr_000000000000", record["id"])
+	require.Equal(t, float64(10), record["sender_amount"])
+	require.Equal(t, "0xabc", record["tracking_complete_transaction_hash"])
+	require.Nil(t, record["tracking_complete_completed_at"])
+	require.Len(t, record, len(TransferColumns()))
+}
+
+func TestPayouts_ResumesFromCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "payouts.jsonl")
+	opts := Options{Format: FormatJSONL, ChunkSize: 1, Checkpoint: filepath.Join(dir, "payouts.checkpoint")}
+
+	failing := &blindpaytest.RoundTripper{
+		T: t,
+		Outs: []json.RawMessage{
+			json.RawMessage(`[{"id":"pa_000000000000","status":"completed"}]`),
+			json.RawMessage(`{"message":"bad gateway"}`),
+		},
+	}
+
+	f, err := OpenFile(output, opts)
+	require.NoError(t, err)
+
+	// The second chunk is not a JSON array and fails to decode.
+	_, err = Payouts(context.Background(), payouts.NewClient(testConfig(failing)), f, opts)
+	require.Error(t, err)
+	require.NoError(t, f.Close())
+	require.FileExists(t, opts.Checkpoint)
+
+	resumed := &blindpaytest.RoundTripper{
+		T: t,
+		Outs: []json.RawMessage{
+			json.RawMessage(`[{"id":"pa_111111111111","status":"completed"}]`),
+			json.RawMessage(`[]`),
+		},
+	}
+
+	f, err = OpenFile(output, opts)
+	require.NoError(t, err)
+	result, err := Payouts(context.Background(), payouts.NewClient(testConfig(resumed)), f, opts)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.True(t, result.Resumed)
+	require.Equal(t, 2, result.Written)
+	require.Equal(t, "limit=1&offset=1", resumed.Requests[0].URL.RawQuery)
+	require.NoFileExists(t, opts.Checkpoint)
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+
+	var ids []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		ids = append(ids, record["id"].(string))
+	}
+	require.Equal(t, []string{"pa_000000000000", "pa_111111111111"}, ids)
+}
+
+func TestPayouts_ResumeDropsTornLine(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "payouts.csv")
+	opts := Options{ChunkSize: 1, Checkpoint: filepath.Join(dir, "payouts.checkpoint")}
+
+	var committed bytes.Buffer
+	out := newRecordWriter(&committed, FormatCSV, PayoutColumns(), 0)
+	require.NoError(t, out.header())
+	values := make([]any, len(payoutColumns))
+	values[0] = "pa_000000000000"
+	require.NoError(t, out.record(values))
+	require.NoError(t, out.flush())
+
+	// The crash happened halfway through the line of the second record.
+	torn := append(bytes.Clone(committed.Bytes()), "pa_111111111111,compl"...)
+	require.NoError(t, os.WriteFile(output, torn, 0o644))
+	require.NoError(t, saveCheckpoint(opts.Checkpoint, checkpoint{
+		Dataset: "payouts",
+		Format:  FormatCSV,
+		Filter:  opts.filter(),
+		Offset:  1,
+		Written: 1,
+		Bytes:   int64(committed.Len()),
+	}))
+
+	resumed := &blindpaytest.RoundTripper{
+		T: t,
+		Outs: []json.RawMessage{
+			json.RawMessage(`[{"id":"pa_111111111111","status":"completed"}]`),
+			json.RawMessage(`[]`),
+		},
+	}
+
+	f, err := OpenFile(output, opts)
+	require.NoError(t, err)
+	result, err := Payouts(context.Background(), payouts.NewClient(testConfig(resumed)), f, opts)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.True(t, result.Resumed)
+	require.Equal(t, 2, result.Written)
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	require.Equal(t, PayoutColumns(), rows[0])
+	require.Equal(t, "pa_000000000000", rows[1][0])
+	require.Equal(t, "pa_111111111111", rows[2][0])
+	require.Equal(t, "completed", rows[2][1])
+}
+
+func TestPayouts_ResumeRejectsShortOutput(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "payouts.jsonl")
+	opts := Options{Format: FormatJSONL, Checkpoint: filepath.Join(dir, "payouts.checkpoint")}
+
+	require.NoError(t, os.WriteFile(output, []byte("{}\n"), 0o644))
+	require.NoError(t, saveCheckpoint(opts.Checkpoint, checkpoint{Dataset: "payouts", Format: FormatJSONL, Filter: opts.filter(), Offset: 10, Written: 10, Bytes: 1024}))
+
+	f, err := OpenFile(output, opts)
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = Payouts(context.Background(), payouts.NewClient(testConfig(&blindpaytest.RoundTripper{T: t})), f, opts)
+	require.ErrorContains(t, err, "fewer than the 1024 recorded by the checkpoint")
+}
+
+func TestPayouts_RejectsForeignCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	require.NoError(t, saveCheckpoint(path, checkpoint{Dataset: "payins", Format: FormatCSV, Filter: Options{}.filter()}))
+
+	_, err := Payouts(context.Background(), payouts.NewClient(testConfig(&blindpaytest.RoundTripper{T: t})), &bytes.Buffer{}, Options{Checkpoint: path})
+	require.ErrorContains(t, err, "belongs to another export")
+}
@@ -58,5 +58,6 @@ func NewClient(cfg *config.Config) *Client {
 // Get retrieves the fee configuration for the instance.
 func (c *Client) Get(ctx context.Context) (*GetResponse, error) {
 	path := fmt.Sprintf("/instances/%s/billing/fees", c.instanceID)
-	return request.Do[*GetResponse](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*GetResponse](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("fees.Get", "/instances/{instance_id}/billing/fees"))
 }
@@ -0,0 +1,12 @@
+package blindpay
+
+import (
+	"github.com/blindpaylabs/blindpay-go/internal/request"
+)
+
+// NewIdempotencyKey returns a random key suitable for the IdempotencyKey field
+// of create params. Generating the key up front lets callers store it next to
+// their own records before the call is made.
+func NewIdempotencyKey() string {
+	return request.NewIdempotencyKey()
+}
@@ -0,0 +1,148 @@
+package blindpay
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/blindpaylabs/blindpay-go/internal/request"
+)
+
+// InstanceOption configures a view returned by Client.ForInstance.
+type InstanceOption func(*instanceOptions)
+
+type instanceOptions struct {
+	apiKey      string
+	credentials CredentialProvider
+}
+
+// WithAPIKey makes the view authenticate with another API key, for instances
+// that do not share the key of the parent client. It replaces the credential
+// provider of the parent client, if any.
+func WithAPIKey(apiKey string) InstanceOption {
+	return func(o *instanceOptions) {
+		o.apiKey = apiKey
+		o.credentials = nil
+	}
+}
+
+// WithInstanceCredentials makes the view ask provider for its API key.
+func WithInstanceCredentials(provider CredentialProvider) InstanceOption {
+	return func(o *instanceOptions) {
+		o.credentials = provider
+	}
+}
+
+// ForInstance returns a view of the client that targets another instance.
+//
+// The view is cheap to create: it shares the HTTP client, retry policy, rate
+// limiter, circuit breaker, middleware and metrics of c, and only its sub-clients
+// are new.
+func (c *Client) ForInstance(instanceID string, opts ...InstanceOption) (*Client, error) {
+	if instanceID == "" {
+		return nil, fmt.Errorf("instance id not provided, get your instance id on blindpay dashboard")
+	}
+
+	o := instanceOptions{apiKey: c.apiKey, credentials: c.credentials}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.apiKey == "" && o.credentials == nil {
+		return nil, fmt.Errorf("api key not provided, get your api key on blindpay dashboard")
+	}
+
+	cfg := *c.cfg
+	cfg.InstanceID = instanceID
+	cfg.APIKey = o.apiKey
+	cfg.Credentials = o.credentials
+
+	view := &Client{
+		baseURL:     c.baseURL,
+		apiKey:      o.apiKey,
+		instanceID:  instanceID,
+		httpClient:  c.httpClient,
+		retry:       c.retry,
+		middleware:  c.middleware,
+		logger:      c.logger,
+		metrics:     c.metrics,
+		rateLimit:   c.rateLimit,
+		breaker:     c.breaker,
+		environment: c.environment,
+		credentials: o.credentials,
+		cache:       c.cache,
+	}
+	view.init(&cfg)
+
+	return view, nil
+}
+
+// InstanceID returns the instance the client acts on.
+func (c *Client) InstanceID() string {
+	return c.instanceID
+}
+
+// Registry maps tenants to client views that share the transport of a base
+// client. It is safe for concurrent use.
+type Registry struct {
+	base *Client
+
+	mu      sync.RWMutex
+	tenants map[string]*Client
+}
+
+// NewRegistry creates an empty registry whose views derive from base.
+func NewRegistry(base *Client) *Registry {
+	return &Registry{
+		base:    base,
+		tenants: make(map[string]*Client),
+	}
+}
+
+// Register creates a view of the base client for the given instance and
+// stores it under tenant, replacing any previous one.
+func (r *Registry) Register(tenant, instanceID string, opts ...InstanceOption) (*Client, error) {
+	if tenant == "" {
+		return nil, request.EmptyParam("tenant")
+	}
+
+	view, err := r.base.ForInstance(instanceID, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.tenants[tenant] = view
+	r.mu.Unlock()
+
+	return view, nil
+}
+
+// Get returns the view registered for tenant.
+func (r *Registry) Get(tenant string) (*Client, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	view, ok := r.tenants[tenant]
+	return view, ok
+}
+
+// Remove forgets the view registered for tenant.
+func (r *Registry) Remove(tenant string) {
+	r.mu.Lock()
+	delete(r.tenants, tenant)
+	r.mu.Unlock()
+}
+
+// Tenants returns the registered tenants in sorted order.
+func (r *Registry) Tenants() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenants := make([]string, 0, len(r.tenants))
+	for tenant := range r.tenants {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+
+	return tenants
+}
@@ -0,0 +1,93 @@
+package blindpay
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blindpaylabs/blindpay-go/internal/blindpaytest"
+)
+
+func TestClient_ForInstance(t *testing.T) {
+	transport := &blindpaytest.RoundTripper{
+		T:      t,
+		Status: http.StatusOK,
+		Out:    json.RawMessage(`{"data":[],"pagination":{"has_more":false}}`),
+	}
+
+	var calls int
+	client, err := New("test_key", "in_000000000000",
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithMiddleware(func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				calls++
+				return next(ctx, req)
+			}
+		}),
+		WithRateLimit(RateLimit{ReadsPerSecond: 100}),
+	)
+	require.NoError(t, err)
+
+	view, err := client.ForInstance("in_000000000001", WithAPIKey("other_key"))
+	require.NoError(t, err)
+	require.Equal(t, "in_000000000001", view.InstanceID())
+	require.Equal(t, "in_000000000000", client.InstanceID())
+	require.Same(t, client.cfg.HTTPClient, view.cfg.HTTPClient)
+	require.Same(t, client.cfg.RateLimiter, view.cfg.RateLimiter)
+
+	_, err = view.Payouts.List(context.Background(), nil)
+	require.NoError(t, err)
+
+	req := transport.Requests[0]
+	require.Equal(t, "/v1/instances/in_000000000001/payouts", req.URL.Path)
+	require.Equal(t, "Bearer other_key", req.Header.Get("Authorization"))
+	require.Equal(t, 1, calls)
+
+	_, err = client.ForInstance("")
+	require.Error(t, err)
+}
+
+func TestRegistry(t *testing.T) {
+	client, err := New("test_key", "in_000000000000")
+	require.NoError(t, err)
+
+	registry := NewRegistry(client)
+
+	acme, err := registry.Register("acme", "in_000000000001")
+	require.NoError(t, err)
+	_, err = registry.Register("globex", "in_000000000002")
+	require.NoError(t, err)
+
+	got, ok := registry.Get("acme")
+	require.True(t, ok)
+	require.Same(t, acme, got)
+	require.Equal(t, []string{"acme", "globex"}, registry.Tenants())
+
+	registry.Remove("acme")
+	_, ok = registry.Get("acme")
+	require.False(t, ok)
+
+	_, err = registry.Register("", "in_000000000003")
+	require.ErrorIs(t, err, ErrInvalidParams)
+}
+
+func TestClient_ForInstanceKeepsCredentials(t *testing.T) {
+	creds := CredentialFunc(func(ctx context.Context) (string, error) {
+		return "rotated_key", nil
+	})
+
+	client, err := New("", "in_000000000000", WithCredentials(creds))
+	require.NoError(t, err)
+
+	view, err := client.ForInstance("in_000000000001")
+	require.NoError(t, err)
+	require.NotNil(t, view.cfg.Credentials)
+
+	view, err = client.ForInstance("in_000000000001", WithAPIKey("other_key"))
+	require.NoError(t, err)
+	require.Nil(t, view.cfg.Credentials)
+	require.Equal(t, "other_key", view.cfg.APIKey)
+}
@@ -65,13 +65,14 @@ func NewClient(cfg *config.Config) *Client {
 // GetMembers retrieves all members of the instance.
 func (c *Client) GetMembers(ctx context.Context) ([]InstanceMember, error) {
 	path := fmt.Sprintf("/instances/%s/members", c.instanceID)
-	return request.Do[[]InstanceMember](c.cfg, ctx, "GET", path, nil)
+	return request.Do[[]InstanceMember](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("instances.GetMembers", "/instances/{instance_id}/members"))
 }
 
 // Update updates the instance settings.
 func (c *Client) Update(ctx context.Context, params *UpdateParams) error {
 	if params == nil {
-		return fmt.Errorf("params cannot be nil")
+		return request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s", c.instanceID)
@@ -90,35 +91,39 @@ func (c *Client) Update(ctx context.Context, params *UpdateParams) error {
 		body["require_passkey"] = *params.RequirePasskey
 	}
 
-	_, err := request.Do[struct{}](c.cfg, ctx, "PUT", path, body)
+	_, err := request.Do[struct{}](c.cfg, ctx, "PUT", path, body,
+		request.WithOperation("instances.Update", "/instances/{instance_id}"),
+		request.WithParams(params))
 	return err
 }
 
 // Delete deletes the instance.
 func (c *Client) Delete(ctx context.Context) error {
 	path := fmt.Sprintf("/instances/%s", c.instanceID)
-	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil)
+	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil,
+		request.WithOperation("instances.Delete", "/instances/{instance_id}"))
 	return err
 }
 
 // DeleteMember removes a member from the instance.
 func (c *Client) DeleteMember(ctx context.Context, memberID string) error {
 	if memberID == "" {
-		return fmt.Errorf("member ID cannot be empty")
+		return request.EmptyParam("member ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/members/%s", c.instanceID, memberID)
-	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil)
+	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil,
+		request.WithOperation("instances.DeleteMember", "/instances/{instance_id}/members/{member_id}"))
 	return err
 }
 
 // UpdateMemberRole updates a member's role in the instance.
 func (c *Client) UpdateMemberRole(ctx context.Context, params *UpdateMemberRoleParams) error {
 	if params == nil {
-		return fmt.Errorf("params cannot be nil")
+		return request.NilParam("params")
 	}
 	if params.MemberID == "" {
-		return fmt.Errorf("member ID cannot be empty")
+		return request.EmptyParam("member ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/members/%s", c.instanceID, params.MemberID)
@@ -127,6 +132,8 @@ func (c *Client) UpdateMemberRole(ctx context.Context, params *UpdateMemberRoleP
 		"user_role": params.Role,
 	}
 
-	_, err := request.Do[struct{}](c.cfg, ctx, "PUT", path, body)
+	_, err := request.Do[struct{}](c.cfg, ctx, "PUT", path, body,
+		request.WithOperation("instances.UpdateMemberRole", "/instances/{instance_id}/members/{member_id}"),
+		request.WithParams(params))
 	return err
 }
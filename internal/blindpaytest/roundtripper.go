@@ -9,17 +9,23 @@ import (
 )
 
 type RoundTripper struct {
-	T          *testing.T
-	In         json.RawMessage
-	Out        json.RawMessage
+	T   *testing.T
+	In  json.RawMessage
+	Out json.RawMessage
+	// Outs, when set, holds the response bodies of successive requests and
+	// takes precedence over Out.
+	Outs       []json.RawMessage
 	Method     string
 	Path       string
 	Status     int
 	StatusText string
 	Header     http.Header
+	Requests   []*http.Request
 }
 
 func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.Requests = append(rt.Requests, req)
+
 	if rt.Method != "" && req.Method != rt.Method {
 		rt.T.Errorf("expected method %s, got %s", rt.Method, req.Method)
 	}
@@ -60,6 +66,14 @@ func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 		statusText = http.StatusText(status)
 	}
 
+	out := rt.Out
+	if len(rt.Outs) > 0 {
+		if len(rt.Requests) > len(rt.Outs) {
+			rt.T.Fatalf("unexpected request %d to %s", len(rt.Requests), req.URL)
+		}
+		out = rt.Outs[len(rt.Requests)-1]
+	}
+
 	header := rt.Header
 	if header == nil {
 		header = http.Header{}
@@ -72,7 +86,7 @@ func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 		StatusCode: status,
 		Status:     statusText,
 		Header:     header,
-		Body:       io.NopCloser(bytes.NewReader(rt.Out)),
+		Body:       io.NopCloser(bytes.NewReader(out)),
 		Request:    req,
 	}, nil
 }
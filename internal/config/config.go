@@ -11,11 +11,18 @@ Config holds the top-level configuration required to initialize Blindpay SDK cli
 It may include both transport-level fields and the instance identifier used to compose API paths.
 */
 type Config struct {
-	BaseURL    string
-	APIKey     string
-	InstanceID string
-	HTTPClient *http.Client
-	UserAgent  string
+	BaseURL        string
+	APIKey         string
+	InstanceID     string
+	HTTPClient     *http.Client
+	UserAgent      string
+	Retry          *request.RetryPolicy
+	Middleware     []request.Middleware
+	Metrics        request.Metrics
+	RateLimiter    *request.RateLimiter
+	CircuitBreaker *request.CircuitBreaker
+	Credentials    request.CredentialProvider
+	Cache          *request.Cache
 }
 
 /*
@@ -28,9 +35,17 @@ func (c *Config) ToRequestConfig() *request.Config {
 	}
 
 	return &request.Config{
-		BaseURL:    c.BaseURL,
-		APIKey:     c.APIKey,
-		HTTPClient: c.HTTPClient,
-		UserAgent:  c.UserAgent,
+		BaseURL:        c.BaseURL,
+		APIKey:         c.APIKey,
+		InstanceID:     c.InstanceID,
+		HTTPClient:     c.HTTPClient,
+		UserAgent:      c.UserAgent,
+		Retry:          c.Retry,
+		Middleware:     c.Middleware,
+		Metrics:        c.Metrics,
+		RateLimiter:    c.RateLimiter,
+		CircuitBreaker: c.CircuitBreaker,
+		Credentials:    c.Credentials,
+		Cache:          c.Cache,
 	}
 }
@@ -0,0 +1,212 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned, wrapped in a *CircuitOpenError, when a call is
+// rejected because the circuit breaker of its endpoint group is open.
+var ErrCircuitOpen = errors.New("blindpay: circuit breaker is open")
+
+// CircuitOpenError reports a call rejected by an open circuit breaker.
+type CircuitOpenError struct {
+	// Group is the endpoint group whose circuit is open, such as "payouts".
+	Group string
+	// RetryAt is when the breaker will let a probe request through.
+	RetryAt time.Time
+}
+
+// Error implements the error interface for CircuitOpenError.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s: %s (retry after %s)", ErrCircuitOpen, e.Group, e.RetryAt.Format(time.RFC3339))
+}
+
+// Is makes errors.Is(err, ErrCircuitOpen) match a CircuitOpenError.
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// Endpoint groups tracked by the circuit breaker. Operations outside these
+// groups are tracked under their package name, such as "bankaccounts".
+const (
+	GroupQuotes    = "quotes"
+	GroupPayouts   = "payouts"
+	GroupPayins    = "payins"
+	GroupReceivers = "receivers"
+	GroupUploads   = "uploads"
+)
+
+// CircuitBreakerPolicy controls when the circuit breaker opens and how it recovers.
+type CircuitBreakerPolicy struct {
+	// FailureThreshold is the number of consecutive 5xx responses or transport
+	// errors in a group that opens its circuit.
+	FailureThreshold int
+	// OpenTimeout is how long an open circuit rejects calls before it lets
+	// probe requests through.
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of probe requests allowed at once while
+	// the circuit is half-open. A successful probe closes the circuit and a
+	// failed one opens it again.
+	HalfOpenRequests int
+}
+
+// DefaultCircuitBreakerPolicy returns the policy used by WithCircuitBreaker
+// when fields are left zero.
+func DefaultCircuitBreakerPolicy() CircuitBreakerPolicy {
+	return CircuitBreakerPolicy{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+		HalfOpenRequests: 1,
+	}
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// CircuitBreaker fails calls fast while an endpoint group is unhealthy.
+//
+// It keeps one circuit per endpoint group, so an incident affecting payouts
+// does not block receivers. It is safe for concurrent use.
+type CircuitBreaker struct {
+	policy CircuitBreakerPolicy
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+
+	now func() time.Time
+}
+
+type circuit struct {
+	state    circuitState
+	failures int
+	openedAt time.Time
+	probes   int
+}
+
+// NewCircuitBreaker creates a circuit breaker. Zero fields of policy take
+// their value from DefaultCircuitBreakerPolicy.
+func NewCircuitBreaker(policy CircuitBreakerPolicy) *CircuitBreaker {
+	defaults := DefaultCircuitBreakerPolicy()
+	if policy.FailureThreshold < 1 {
+		policy.FailureThreshold = defaults.FailureThreshold
+	}
+	if policy.OpenTimeout <= 0 {
+		policy.OpenTimeout = defaults.OpenTimeout
+	}
+	if policy.HalfOpenRequests < 1 {
+		policy.HalfOpenRequests = defaults.HalfOpenRequests
+	}
+
+	return &CircuitBreaker{
+		policy:   policy,
+		circuits: make(map[string]*circuit),
+		now:      time.Now,
+	}
+}
+
+// allow reports whether an attempt to the given group may proceed. When it
+// may, the returned function must be called exactly once with the outcome of
+// the attempt, with sent set to false if the attempt was abandoned.
+func (b *CircuitBreaker) allow(group string) (func(failed, sent bool), error) {
+	if b == nil {
+		return func(bool, bool) {}, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[group]
+	if !ok {
+		c = &circuit{}
+		b.circuits[group] = c
+	}
+
+	now := b.now()
+	retryAt := c.openedAt.Add(b.policy.OpenTimeout)
+
+	switch c.state {
+	case circuitOpen:
+		if now.Before(retryAt) {
+			return nil, &CircuitOpenError{Group: group, RetryAt: retryAt}
+		}
+		c.state = circuitHalfOpen
+		c.probes = 0
+		fallthrough
+	case circuitHalfOpen:
+		if c.probes >= b.policy.HalfOpenRequests {
+			return nil, &CircuitOpenError{Group: group, RetryAt: now}
+		}
+		c.probes++
+		return func(failed, sent bool) { b.record(c, true, failed, sent) }, nil
+	}
+
+	return func(failed, sent bool) { b.record(c, false, failed, sent) }, nil
+}
+
+// record updates the circuit with the outcome of an attempt.
+func (b *CircuitBreaker) record(c *circuit, probe, failed, sent bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe && c.state == circuitHalfOpen {
+		c.probes--
+	}
+	// Attempts started before the circuit opened say nothing about recovery.
+	if !sent || (!probe && c.state != circuitClosed) {
+		return
+	}
+
+	if !failed {
+		c.state = circuitClosed
+		c.failures = 0
+		return
+	}
+
+	c.failures++
+	if c.state == circuitHalfOpen || c.failures >= b.policy.FailureThreshold {
+		c.state = circuitOpen
+		c.openedAt = b.now()
+	}
+}
+
+// circuitOutcome classifies an attempt for the circuit breaker. A 5xx
+// response or a transport error is a failure; an error caused by the caller's
+// own context is not counted at all.
+func circuitOutcome(ctx context.Context, status int, err error) (failed, sent bool) {
+	if err != nil {
+		return true, ctx.Err() == nil
+	}
+	return status >= http.StatusInternalServerError, true
+}
+
+// circuitGroup returns the endpoint group of an operation name such as
+// "payouts.CreateEvm" or "payins.quotes.Create".
+func circuitGroup(req *Request) string {
+	name := req.Operation
+	if name == "" {
+		return "default"
+	}
+
+	parts := strings.Split(name, ".")
+	for _, part := range parts[:len(parts)-1] {
+		if part == GroupQuotes {
+			return GroupQuotes
+		}
+	}
+	if parts[0] == "upload" {
+		return GroupUploads
+	}
+
+	return parts[0]
+}
@@ -0,0 +1,133 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testBreaker(policy CircuitBreakerPolicy) (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	b := NewCircuitBreaker(policy)
+	b.now = clock.Now
+	return b, clock
+}
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	b, _ := testBreaker(CircuitBreakerPolicy{FailureThreshold: 2})
+
+	for i := 0; i < 2; i++ {
+		done, err := b.allow(GroupPayouts)
+		require.NoError(t, err)
+		done(true, true)
+	}
+
+	_, err := b.allow(GroupPayouts)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+
+	var openErr *CircuitOpenError
+	require.ErrorAs(t, err, &openErr)
+	require.Equal(t, GroupPayouts, openErr.Group)
+
+	// Other groups are unaffected.
+	_, err = b.allow(GroupReceivers)
+	require.NoError(t, err)
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	b, _ := testBreaker(CircuitBreakerPolicy{FailureThreshold: 2})
+
+	for _, failed := range []bool{true, false, true} {
+		done, err := b.allow(GroupQuotes)
+		require.NoError(t, err)
+		done(failed, true)
+	}
+
+	_, err := b.allow(GroupQuotes)
+	require.NoError(t, err)
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	b, clock := testBreaker(CircuitBreakerPolicy{FailureThreshold: 1, OpenTimeout: time.Second})
+
+	done, _ := b.allow(GroupPayins)
+	done(true, true)
+
+	clock.Advance(time.Second)
+
+	probe, err := b.allow(GroupPayins)
+	require.NoError(t, err)
+
+	_, err = b.allow(GroupPayins)
+	require.ErrorIs(t, err, ErrCircuitOpen, "only one probe at a time")
+
+	probe(true, true)
+	_, err = b.allow(GroupPayins)
+	require.ErrorIs(t, err, ErrCircuitOpen, "a failed probe opens the circuit again")
+
+	clock.Advance(time.Second)
+	probe, err = b.allow(GroupPayins)
+	require.NoError(t, err)
+	probe(false, true)
+
+	done, err = b.allow(GroupPayins)
+	require.NoError(t, err)
+	done(false, true)
+}
+
+func TestCircuitBreaker_AbandonedProbeIsReleased(t *testing.T) {
+	b, clock := testBreaker(CircuitBreakerPolicy{FailureThreshold: 1, OpenTimeout: time.Second})
+
+	done, _ := b.allow(GroupUploads)
+	done(true, true)
+	clock.Advance(time.Second)
+
+	probe, err := b.allow(GroupUploads)
+	require.NoError(t, err)
+	probe(false, false)
+
+	_, err = b.allow(GroupUploads)
+	require.NoError(t, err)
+}
+
+func TestCircuitGroup(t *testing.T) {
+	tests := map[string]string{
+		"payouts.CreateEvm":       GroupPayouts,
+		"quotes.Create":           GroupQuotes,
+		"payins.quotes.Create":    GroupQuotes,
+		"transfers.quotes.Create": GroupQuotes,
+		"payins.List":             GroupPayins,
+		"receivers.Get":           GroupReceivers,
+		"upload.Upload":           GroupUploads,
+		"bankaccounts.List":       "bankaccounts",
+		"":                        "default",
+	}
+
+	for operation, group := range tests {
+		require.Equal(t, group, circuitGroup(&Request{Operation: operation}), operation)
+	}
+}
+
+func TestDo_CircuitBreakerFailsFast(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusServiceUnavailable},
+		{status: http.StatusServiceUnavailable},
+		{status: http.StatusServiceUnavailable},
+	}}
+
+	cfg := testConfig(transport, fastPolicy())
+	cfg.CircuitBreaker = NewCircuitBreaker(CircuitBreakerPolicy{FailureThreshold: 2})
+
+	_, err := Do[map[string]string](cfg, context.Background(), "GET", "/payouts", nil, WithOperation("payouts.List", "/payouts"))
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr, "the last response is reported when the circuit opens mid-retry")
+	require.Len(t, transport.requests, 2)
+
+	_, err = Do[map[string]string](cfg, context.Background(), "GET", "/payouts", nil, WithOperation("payouts.List", "/payouts"))
+	require.True(t, errors.Is(err, ErrCircuitOpen))
+	require.Len(t, transport.requests, 2)
+}
@@ -0,0 +1,305 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheKeyPrefix starts every key written by a Cache to its store.
+const cacheKeyPrefix = "blindpay:"
+
+// CacheStore stores cached response bodies. Implementations must be safe for
+// concurrent use.
+type CacheStore interface {
+	// Get returns the value stored under key, and whether it was found and
+	// has not expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// DeletePrefix removes every value whose key starts with prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// CachePolicy controls which operations are cached and for how long.
+type CachePolicy struct {
+	// TTL maps operation names, such as "available.GetRails", to the time
+	// their responses are kept. Operations that are not listed are not cached.
+	TTL map[string]time.Duration
+	// Store holds the cached responses. When nil, an in-memory store is used.
+	Store CacheStore
+}
+
+// DefaultCacheTTL returns the cache TTLs used for zero CachePolicy.TTL: one
+// hour for the available rails, bank details, NAICS codes and swift codes,
+// and five minutes for the instance fees.
+func DefaultCacheTTL() map[string]time.Duration {
+	return map[string]time.Duration{
+		"available.GetRails":                time.Hour,
+		"available.GetBankDetails":          time.Hour,
+		"available.GetNaicsCodes":           time.Hour,
+		"available.GetSwiftCodeBankDetails": time.Hour,
+		"fees.Get":                          5 * time.Minute,
+	}
+}
+
+// Cache serves successful GET responses of the operations listed in its
+// policy from a CacheStore. Concurrent misses for the same key share a single
+// request. It is safe for concurrent use.
+type Cache struct {
+	ttl   map[string]time.Duration
+	store CacheStore
+
+	mu       sync.Mutex
+	inflight map[string]*flight
+}
+
+// flight is a request shared by concurrent misses for the same key. It keeps
+// the raw response, which every caller decodes into a value of its own.
+type flight struct {
+	done chan struct{}
+	resp *Response
+	err  error
+}
+
+// NewCache creates a cache from policy.
+func NewCache(policy CachePolicy) *Cache {
+	ttl := policy.TTL
+	if ttl == nil {
+		ttl = DefaultCacheTTL()
+	}
+	store := policy.Store
+	if store == nil {
+		store = NewMemoryCacheStore()
+	}
+
+	return &Cache{
+		ttl:      ttl,
+		store:    store,
+		inflight: make(map[string]*flight),
+	}
+}
+
+// Invalidate removes the cached responses of the given operations, or of all
+// operations when none is given.
+func (c *Cache) Invalidate(ctx context.Context, operations ...string) error {
+	if len(operations) == 0 {
+		return c.store.DeletePrefix(ctx, cacheKeyPrefix)
+	}
+
+	for _, operation := range operations {
+		if err := c.store.DeletePrefix(ctx, cacheKeyPrefix+operation+":"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// caches reports whether responses to req are cached.
+func (c *Cache) caches(req *Request) bool {
+	return c != nil && req.Method == http.MethodGet && c.ttl[req.Operation] > 0
+}
+
+// do returns the cached response for req, decoded by decode, or fetches it
+// with fetch and caches it when successful.
+//
+// Concurrent misses share one fetch, which runs on a context detached from
+// the caller that started it, bounded by the deadline of that caller, so that
+// the other callers are not failed by its cancellation.
+func (c *Cache) do(ctx context.Context, req *Request, decode func(*Response) (*Response, error), fetch func(context.Context) (*Response, error)) (*Response, error) {
+	key := cacheKeyPrefix + req.Operation + ":" + req.Path
+
+	// A failing store only costs the cache hit.
+	if data, ok, err := c.store.Get(ctx, key); err == nil && ok {
+		resp, err := decode(&Response{StatusCode: http.StatusOK, Body: bytes.Clone(data), Cached: true})
+		if err == nil {
+			return resp, nil
+		}
+	}
+
+	c.mu.Lock()
+	f, ok := c.inflight[key]
+	if !ok {
+		f = &flight{done: make(chan struct{})}
+		c.inflight[key] = f
+		go c.fetch(ctx, key, c.ttl[req.Operation], f, fetch)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		return &Response{}, ctx.Err()
+	}
+
+	if f.resp == nil {
+		return &Response{}, f.err
+	}
+	return decode(&Response{
+		StatusCode: f.resp.StatusCode,
+		Header:     f.resp.Header.Clone(),
+		Body:       bytes.Clone(f.resp.Body),
+		Attempts:   f.resp.Attempts,
+	})
+}
+
+// fetch runs the request of flight f and caches its response when successful.
+func (c *Cache) fetch(ctx context.Context, key string, ttl time.Duration, f *flight, fetch func(context.Context) (*Response, error)) {
+	fetchCtx := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		fetchCtx, cancel = context.WithDeadline(fetchCtx, deadline)
+		defer cancel()
+	}
+
+	resp, err := fetch(fetchCtx)
+	if resp != nil && resp.StatusCode != 0 {
+		// Errors decoding a response are left to each caller to find.
+		f.resp = &Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       resp.Body,
+			Attempts:   resp.Attempts,
+		}
+	} else {
+		f.err = err
+	}
+	if err == nil && resp.StatusCode == http.StatusOK {
+		_ = c.store.Set(fetchCtx, key, resp.Body, ttl)
+	}
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	c.mu.Unlock()
+	close(f.done)
+}
+
+// MemoryCacheStore is a CacheStore that keeps values in memory.
+type MemoryCacheStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCacheStore creates an empty in-memory store.
+func NewMemoryCacheStore() *MemoryCacheStore {
+	return &MemoryCacheStore{
+		entries: make(map[string]memoryCacheEntry),
+	}
+}
+
+// Get returns the value stored under key unless it has expired.
+func (s *MemoryCacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if time.Now().After(entry.expires) {
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set stores value under key for ttl, and drops expired entries.
+func (s *MemoryCacheStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range s.entries {
+		if now.After(entry.expires) {
+			delete(s.entries, k)
+		}
+	}
+
+	s.entries[key] = memoryCacheEntry{value: value, expires: now.Add(ttl)}
+	return nil
+}
+
+// DeletePrefix removes every value whose key starts with prefix.
+func (s *MemoryCacheStore) DeletePrefix(ctx context.Context, prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
+
+// RedisClient is the subset of a Redis client used by RedisCacheStore. It is
+// small enough to be adapted from any Redis library in a few lines.
+type RedisClient interface {
+	// Get returns the value of key, and false when the key does not exist.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key with the given expiration (SET key value PX ttl).
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Scan returns the keys matching the glob pattern (SCAN with MATCH).
+	Scan(ctx context.Context, pattern string) ([]string, error)
+	// Del removes the given keys.
+	Del(ctx context.Context, keys ...string) error
+}
+
+// RedisCacheStore is a CacheStore backed by Redis, which lets several
+// processes share cached responses.
+type RedisCacheStore struct {
+	client    RedisClient
+	namespace string
+}
+
+// NewRedisCacheStore creates a store that keeps values in Redis under keys
+// prefixed by namespace, which may be empty.
+func NewRedisCacheStore(client RedisClient, namespace string) *RedisCacheStore {
+	return &RedisCacheStore{
+		client:    client,
+		namespace: namespace,
+	}
+}
+
+// Get returns the value stored under key.
+func (s *RedisCacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return s.client.Get(ctx, s.namespace+key)
+}
+
+// Set stores value under key for ttl.
+func (s *RedisCacheStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, s.namespace+key, value, ttl)
+}
+
+// DeletePrefix removes every value whose key starts with prefix.
+func (s *RedisCacheStore) DeletePrefix(ctx context.Context, prefix string) error {
+	keys, err := s.client.Scan(ctx, escapeGlob(s.namespace+prefix)+"*")
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.client.Del(ctx, keys...)
+}
+
+// escapeGlob escapes the characters that have a meaning in Redis glob patterns.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,190 @@
+package request
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingTransport answers every request with the same body after release
+// is closed, counting the requests it receives.
+type countingTransport struct {
+	body    string
+	release chan struct{}
+	count   atomic.Int32
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.count.Add(1)
+	if c.release != nil {
+		<-c.release
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(c.body)),
+		Request:    req,
+	}, nil
+}
+
+func getRails(cfg *Config) ([]map[string]string, error) {
+	return Do[[]map[string]string](cfg, context.Background(), "GET", "/available/rails", nil,
+		WithOperation("available.GetRails", "/available/rails"))
+}
+
+func TestCache_ServesHits(t *testing.T) {
+	transport := &countingTransport{body: `[{"label":"Wire","value":"wire","country":"US"}]`}
+
+	cfg := testConfig(transport, nil)
+	cfg.Cache = NewCache(CachePolicy{})
+
+	var cached []bool
+	cfg.Middleware = []Middleware{func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			resp, err := next(ctx, req)
+			cached = append(cached, resp.Cached)
+			return resp, err
+		}
+	}}
+
+	for i := 0; i < 3; i++ {
+		rails, err := getRails(cfg)
+		require.NoError(t, err)
+		require.Equal(t, "wire", rails[0]["value"])
+	}
+	require.Equal(t, int32(1), transport.count.Load())
+	require.Equal(t, []bool{false, true, true}, cached)
+
+	require.NoError(t, cfg.Cache.Invalidate(context.Background(), "available.GetRails"))
+
+	_, err := getRails(cfg)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), transport.count.Load())
+}
+
+func TestCache_SharesConcurrentMisses(t *testing.T) {
+	transport := &countingTransport{body: `[]`, release: make(chan struct{})}
+
+	cfg := testConfig(transport, nil)
+	cfg.Cache = NewCache(CachePolicy{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := getRails(cfg)
+			require.NoError(t, err)
+		}()
+	}
+
+	require.Eventually(t, func() bool { return transport.count.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(transport.release)
+	wg.Wait()
+
+	require.Equal(t, int32(1), transport.count.Load())
+}
+
+func TestCache_DecodesForEachCaller(t *testing.T) {
+	transport := &countingTransport{body: `{"value":"wire"}`, release: make(chan struct{})}
+
+	cfg := testConfig(transport, nil)
+	cfg.Cache = NewCache(CachePolicy{})
+
+	type rail struct {
+		Value string `json:"value"`
+	}
+	getRail := func(ctx context.Context) (*rail, error) {
+		return Do[*rail](cfg, ctx, "GET", "/available/rails", nil,
+			WithOperation("available.GetRails", "/available/rails"))
+	}
+
+	// The caller starting the shared request gives up before it completes.
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error)
+	go func() {
+		_, err := getRail(leaderCtx)
+		leaderErr <- err
+	}()
+	require.Eventually(t, func() bool { return transport.count.Load() == 1 }, time.Second, time.Millisecond)
+
+	results := make([]*rail, 2)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			results[i], err = getRail(context.Background())
+			require.NoError(t, err)
+		}(i)
+	}
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-leaderErr, context.Canceled)
+	close(transport.release)
+	wg.Wait()
+
+	require.Equal(t, int32(1), transport.count.Load())
+	require.NotSame(t, results[0], results[1])
+	results[0].Value = "changed"
+	require.Equal(t, "wire", results[1].Value)
+
+	cached, err := getRail(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "wire", cached.Value)
+	require.Equal(t, int32(1), transport.count.Load())
+}
+
+func TestCache_SkipsUnlistedOperationsAndErrors(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusServiceUnavailable, body: `{"message":"unavailable"}`},
+		{status: http.StatusOK, body: `[]`},
+		{status: http.StatusOK, body: `[]`},
+		{status: http.StatusOK, body: `[]`},
+	}}
+
+	cfg := testConfig(transport, nil)
+	cfg.Cache = NewCache(CachePolicy{TTL: map[string]time.Duration{"available.GetRails": time.Minute}})
+
+	_, err := getRails(cfg)
+	require.Error(t, err)
+	_, err = getRails(cfg)
+	require.NoError(t, err)
+	_, err = getRails(cfg)
+	require.NoError(t, err)
+	require.Len(t, transport.requests, 2)
+
+	for i := 0; i < 2; i++ {
+		_, err = Do[[]map[string]string](cfg, context.Background(), "GET", "/available/naics", nil,
+			WithOperation("available.GetNaicsCodes", "/available/naics"))
+		require.NoError(t, err)
+	}
+	require.Len(t, transport.requests, 4)
+}
+
+func TestMemoryCacheStore_Expires(t *testing.T) {
+	store := NewMemoryCacheStore()
+	ctx := context.Background()
+
+	require.NoError(t, store.Set(ctx, "blindpay:a", []byte("1"), time.Millisecond))
+	require.NoError(t, store.Set(ctx, "blindpay:b", []byte("2"), time.Hour))
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok, err := store.Get(ctx, "blindpay:a")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	value, ok, err := store.Get(ctx, "blindpay:b")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte("2"), value)
+}
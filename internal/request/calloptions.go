@@ -0,0 +1,168 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// CallOptions are per-call settings carried by a context. They let a single
+// call deviate from the client configuration without changing method signatures.
+type CallOptions struct {
+	// Timeout bounds the whole call, retries included. When set, it replaces
+	// the timeout of the client's http.Client for this call.
+	Timeout time.Duration
+	// Header holds extra headers sent with the call. They cannot replace the
+	// headers set by the SDK, such as Authorization.
+	Header http.Header
+	// IdempotencyKey is sent in the Idempotency-Key header of the first
+	// write call made with the context, unless that call has a key of its
+	// own. It is never sent with GET and DELETE requests.
+	IdempotencyKey string
+	// Retry replaces the client retry policy for the call.
+	Retry *RetryPolicy
+	// InstanceID makes the call act on another instance than the client's.
+	InstanceID string
+
+	// idempotencyKeyUsed records that IdempotencyKey was sent. It is shared
+	// by the contexts derived with WithCallOptions, so that the key is sent
+	// once in total.
+	idempotencyKeyUsed *atomic.Bool
+}
+
+// CallOption configures CallOptions.
+type CallOption func(*CallOptions)
+
+type callOptionsKey struct{}
+
+// WithCallOptions returns a context carrying the given per-call options on
+// top of those already carried by ctx.
+func WithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	co := CallOptions{}
+	if parent := callOptionsFrom(ctx); parent != nil {
+		co = *parent
+		co.Header = parent.Header.Clone()
+	}
+	for _, opt := range opts {
+		opt(&co)
+	}
+	return context.WithValue(ctx, callOptionsKey{}, &co)
+}
+
+// callOptionsFrom returns the per-call options carried by ctx, or nil.
+func callOptionsFrom(ctx context.Context) *CallOptions {
+	co, _ := ctx.Value(callOptionsKey{}).(*CallOptions)
+	return co
+}
+
+// CallTimeout bounds the whole call, retries included.
+func CallTimeout(d time.Duration) CallOption {
+	return func(co *CallOptions) {
+		co.Timeout = d
+	}
+}
+
+// CallHeader adds a header to the call.
+func CallHeader(key, value string) CallOption {
+	return func(co *CallOptions) {
+		if co.Header == nil {
+			co.Header = http.Header{}
+		}
+		co.Header.Add(key, value)
+	}
+}
+
+// CallIdempotencyKey sends the given Idempotency-Key with the first write
+// call made with the context. Sending the same key with two different
+// requests would make the API answer the second with the result of the first.
+func CallIdempotencyKey(key string) CallOption {
+	return func(co *CallOptions) {
+		co.IdempotencyKey = key
+		co.idempotencyKeyUsed = new(atomic.Bool)
+	}
+}
+
+// takeIdempotencyKey returns the IdempotencyKey for a request with the given
+// method, unless it was already taken or the method is GET or DELETE.
+func (co *CallOptions) takeIdempotencyKey(method string) string {
+	if co == nil || co.IdempotencyKey == "" || method == http.MethodGet || method == http.MethodDelete {
+		return ""
+	}
+	if co.idempotencyKeyUsed != nil && !co.idempotencyKeyUsed.CompareAndSwap(false, true) {
+		return ""
+	}
+	return co.IdempotencyKey
+}
+
+// CallRetryPolicy replaces the client retry policy for the call.
+func CallRetryPolicy(policy RetryPolicy) CallOption {
+	return func(co *CallOptions) {
+		co.Retry = &policy
+	}
+}
+
+// CallInstanceID makes the call act on the given instance.
+func CallInstanceID(instanceID string) CallOption {
+	return func(co *CallOptions) {
+		co.InstanceID = instanceID
+	}
+}
+
+// apply returns the configuration, context and request adjusted for the
+// per-call options. The returned cancel function must always be called.
+func (co *CallOptions) apply(cfg *Config, ctx context.Context, req *Request) (*Config, context.Context, context.CancelFunc) {
+	if co == nil {
+		return cfg, ctx, func() {}
+	}
+
+	copied := *cfg
+	cfg = &copied
+
+	for key, values := range co.Header {
+		if req.Header.Get(key) == "" {
+			req.Header[http.CanonicalHeaderKey(key)] = values
+		}
+	}
+
+	if req.Header.Get("Idempotency-Key") == "" {
+		if key := co.takeIdempotencyKey(req.Method); key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+	}
+
+	if co.Retry != nil {
+		cfg.Retry = co.Retry
+	}
+
+	if co.InstanceID != "" && co.InstanceID != cfg.InstanceID {
+		req.Path = replaceInstanceID(req.Path, cfg.InstanceID, co.InstanceID)
+		req.InstanceID = co.InstanceID
+		cfg.InstanceID = co.InstanceID
+	}
+
+	cancel := func() {}
+	if co.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, co.Timeout)
+		if cfg.HTTPClient != nil && cfg.HTTPClient.Timeout != 0 {
+			client := *cfg.HTTPClient
+			client.Timeout = 0
+			cfg.HTTPClient = &client
+		}
+	}
+
+	return cfg, ctx, cancel
+}
+
+// replaceInstanceID rewrites the instance ID of a path built for another
+// instance, either as its /instances/{instance_id} prefix or as its
+// instance_id query parameter.
+func replaceInstanceID(path, from, to string) string {
+	prefix := "/instances/" + from
+	if path == prefix || strings.HasPrefix(path, prefix+"/") || strings.HasPrefix(path, prefix+"?") {
+		return "/instances/" + to + path[len(prefix):]
+	}
+
+	return strings.Replace(path, "instance_id="+from, "instance_id="+to, 1)
+}
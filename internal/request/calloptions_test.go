@@ -0,0 +1,158 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo_CallOptions(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusOK, body: `{}`},
+	}}
+
+	cfg := testConfig(transport, nil)
+	cfg.InstanceID = "in_000000000000"
+
+	ctx := WithCallOptions(context.Background(),
+		CallHeader("X-Correlation-Id", "corr-1"),
+		CallHeader("Authorization", "Bearer other"),
+		CallIdempotencyKey("key-1"),
+	)
+	ctx = WithCallOptions(ctx, CallInstanceID("in_000000000001"))
+
+	_, err := Do[map[string]string](cfg, ctx, "POST", "/instances/in_000000000000/payouts/evm", map[string]string{})
+	require.NoError(t, err)
+
+	req := transport.requests[0]
+	require.Equal(t, "/instances/in_000000000001/payouts/evm", req.URL.Path)
+	require.Equal(t, "corr-1", req.Header.Get("X-Correlation-Id"))
+	require.Equal(t, "Bearer test_key", req.Header.Get("Authorization"))
+	require.Equal(t, "key-1", req.Header.Get("Idempotency-Key"))
+	require.Equal(t, "in_000000000000", cfg.InstanceID, "the shared config is left untouched")
+}
+
+func TestDo_CallRetryPolicy(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusServiceUnavailable},
+		{status: http.StatusOK, body: `{}`},
+	}}
+
+	ctx := WithCallOptions(context.Background(), CallRetryPolicy(*fastPolicy()))
+
+	_, err := Do[map[string]string](testConfig(transport, nil), ctx, "GET", "/payouts", nil)
+	require.NoError(t, err)
+	require.Len(t, transport.requests, 2)
+}
+
+func TestDo_CallTimeout(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusOK, body: `{}`},
+	}}
+
+	cfg := testConfig(transport, nil)
+	cfg.HTTPClient.Timeout = time.Second
+
+	ctx := WithCallOptions(context.Background(), CallTimeout(time.Minute))
+
+	_, err := Do[map[string]string](cfg, ctx, "GET", "/payouts", nil)
+	require.NoError(t, err)
+
+	deadline, ok := transport.requests[0].Context().Deadline()
+	require.True(t, ok)
+	require.Greater(t, time.Until(deadline), 30*time.Second)
+	require.Equal(t, time.Second, cfg.HTTPClient.Timeout)
+}
+
+func TestReplaceInstanceID(t *testing.T) {
+	require.Equal(t, "/instances/b/payouts", replaceInstanceID("/instances/a/payouts", "a", "b"))
+	require.Equal(t, "/instances/b", replaceInstanceID("/instances/a", "a", "b"))
+	require.Equal(t, "/upload?instance_id=b", replaceInstanceID("/upload?instance_id=a", "a", "b"))
+	require.Equal(t, "/instances/ab/payouts", replaceInstanceID("/instances/ab/payouts", "a", "b"))
+	require.Equal(t, "/available/rails", replaceInstanceID("/available/rails", "a", "b"))
+}
+
+func TestDo_WithIdempotency(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusOK, body: `{}`},
+		{status: http.StatusOK, body: `{}`},
+		{status: http.StatusOK, body: `{}`},
+	}}
+	cfg := testConfig(transport, nil)
+	ctx := WithCallOptions(context.Background(), CallIdempotencyKey("call-key"))
+
+	var sent string
+	_, err := Do[map[string]string](cfg, ctx, "POST", "/payouts", map[string]string{}, WithIdempotency("params-key", &sent))
+	require.NoError(t, err)
+	require.Equal(t, "params-key", sent)
+
+	_, err = Do[map[string]string](cfg, ctx, "POST", "/payouts", map[string]string{}, WithIdempotency("", &sent))
+	require.NoError(t, err)
+	require.Equal(t, "call-key", sent)
+
+	_, err = Do[map[string]string](cfg, context.Background(), "POST", "/payouts", map[string]string{}, WithIdempotency("", &sent))
+	require.NoError(t, err)
+	require.Len(t, sent, 36)
+
+	require.Equal(t, "params-key", transport.requests[0].Header.Get("Idempotency-Key"))
+	require.Equal(t, "call-key", transport.requests[1].Header.Get("Idempotency-Key"))
+	require.Equal(t, sent, transport.requests[2].Header.Get("Idempotency-Key"))
+}
+
+func TestDo_WithIdempotencyReportsKeyOnError(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusGatewayTimeout, body: `{"message":"timeout"}`},
+		{status: http.StatusGatewayTimeout, body: `{"message":"timeout"}`},
+	}}
+	cfg := testConfig(transport, nil)
+
+	var sent string
+	_, err := Do[map[string]string](cfg, context.Background(), "POST", "/payouts", map[string]string{}, WithIdempotency("", &sent))
+
+	var createErr *CreateError
+	require.ErrorAs(t, err, &createErr)
+	require.NotEmpty(t, createErr.IdempotencyKey)
+	require.Equal(t, sent, createErr.IdempotencyKey)
+	require.Equal(t, createErr.IdempotencyKey, transport.requests[0].Header.Get("Idempotency-Key"))
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusGatewayTimeout, apiErr.StatusCode)
+
+	_, err = Do[map[string]string](cfg, context.Background(), "GET", "/payouts", nil)
+	require.False(t, errors.As(err, &createErr), "calls without WithIdempotency are not wrapped")
+}
+
+func TestDo_CallIdempotencyKeyIsSentOnce(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusOK, body: `{}`},
+		{status: http.StatusOK, body: `{}`},
+		{status: http.StatusOK, body: `{}`},
+		{status: http.StatusOK, body: `{}`},
+	}}
+	cfg := testConfig(transport, nil)
+	ctx := WithCallOptions(context.Background(), CallIdempotencyKey("call-key"))
+
+	_, err := Do[map[string]string](cfg, ctx, "GET", "/payouts", nil)
+	require.NoError(t, err)
+	_, err = Do[map[string]string](cfg, ctx, "DELETE", "/payouts/pa_000000000000", nil)
+	require.NoError(t, err)
+
+	// Contexts derived from ctx share its key.
+	derived := WithCallOptions(ctx, CallHeader("X-Correlation-Id", "corr-1"))
+	_, err = Do[map[string]string](cfg, derived, "POST", "/payouts", map[string]string{}, WithIdempotency("", nil))
+	require.NoError(t, err)
+	_, err = Do[map[string]string](cfg, ctx, "POST", "/payouts", map[string]string{}, WithIdempotency("", nil))
+	require.NoError(t, err)
+
+	require.Empty(t, transport.requests[0].Header.Get("Idempotency-Key"))
+	require.Empty(t, transport.requests[1].Header.Get("Idempotency-Key"))
+	require.Equal(t, "call-key", transport.requests[2].Header.Get("Idempotency-Key"))
+	second := transport.requests[3].Header.Get("Idempotency-Key")
+	require.NotEmpty(t, second)
+	require.NotEqual(t, "call-key", second)
+}
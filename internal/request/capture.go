@@ -0,0 +1,107 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ResponseMeta describes the HTTP exchange behind an API call. It is filled
+// in for calls made with a context returned by WithResponseCapture, whether
+// the call succeeds or fails.
+type ResponseMeta struct {
+	// Operation is the SDK operation name, such as "payouts.CreateEvm".
+	Operation string
+	// StatusCode is the HTTP status of the last attempt, or 0 if no response was received.
+	StatusCode int
+	// Header holds the response headers of the last attempt.
+	Header http.Header
+	// Body is the raw response body of the last attempt.
+	Body []byte
+	// Duration is the time spent on the call, including retries and waits.
+	Duration time.Duration
+	// Attempts is the number of HTTP attempts made.
+	Attempts int
+	// RequestID is the server's request identifier, read from the
+	// X-Request-Id or Request-Id response header.
+	RequestID string
+	// TraceID is the server's trace identifier, read from the X-Trace-Id
+	// response header or the trace_id of an error response.
+	TraceID string
+	// RateLimit is the rate-limit state reported by the X-RateLimit-* headers.
+	RateLimit RateLimitState
+}
+
+// RateLimitState is the rate-limit window reported by the API. Fields are
+// zero when the corresponding header is absent.
+type RateLimitState struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+type captureKey struct{}
+
+// capturer is the destination of the ResponseMeta of the calls made with a
+// capture context. Concurrent calls write it one at a time.
+type capturer struct {
+	mu   sync.Mutex
+	meta *ResponseMeta
+}
+
+// WithResponseCapture returns a context that makes every API call using it
+// fill in meta. When the context is shared by several calls, possibly
+// concurrent ones, meta describes the last one to complete; it must only be
+// read once they are all done.
+func WithResponseCapture(ctx context.Context, meta *ResponseMeta) context.Context {
+	return context.WithValue(ctx, captureKey{}, &capturer{meta: meta})
+}
+
+// capture fills in the ResponseMeta registered on ctx, if any.
+func capture(ctx context.Context, req *Request, resp *Response, err error, duration time.Duration) {
+	c, ok := ctx.Value(captureKey{}).(*capturer)
+	if !ok || c.meta == nil {
+		return
+	}
+
+	meta := ResponseMeta{
+		Operation: req.Operation,
+		Duration:  duration,
+	}
+	if resp != nil {
+		meta.StatusCode = resp.StatusCode
+		meta.Header = resp.Header
+		meta.Body = resp.Body
+		meta.Attempts = resp.Attempts
+	}
+
+	if meta.Header != nil {
+		meta.RequestID = meta.Header.Get("X-Request-Id")
+		if meta.RequestID == "" {
+			meta.RequestID = meta.Header.Get("Request-Id")
+		}
+		meta.TraceID = meta.Header.Get("X-Trace-Id")
+		meta.RateLimit = rateLimitState(meta.Header, time.Now())
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && meta.TraceID == "" {
+		meta.TraceID = apiErr.TraceID
+	}
+
+	c.mu.Lock()
+	*c.meta = meta
+	c.mu.Unlock()
+}
+
+// rateLimitState reads the X-RateLimit-* response headers.
+func rateLimitState(header http.Header, now time.Time) RateLimitState {
+	var state RateLimitState
+	state.Limit, _ = strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	state.Remaining, _ = strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	state.Reset, _ = parseRateLimitReset(header.Get("X-RateLimit-Reset"), now)
+	return state
+}
@@ -0,0 +1,81 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo_CapturesResponseMeta(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusServiceUnavailable},
+		{
+			status: http.StatusOK,
+			header: http.Header{
+				"X-Request-Id":          {"req_000000000000"},
+				"X-Ratelimit-Limit":     {"100"},
+				"X-Ratelimit-Remaining": {"99"},
+				"X-Ratelimit-Reset":     {"30"},
+			},
+			body: `{"id":"pa_000000000000"}`,
+		},
+	}}
+
+	var meta ResponseMeta
+	ctx := WithResponseCapture(context.Background(), &meta)
+
+	_, err := Do[map[string]string](testConfig(transport, fastPolicy()), ctx, "GET", "/payouts/pa_000000000000", nil,
+		WithOperation("payouts.Get", "/instances/{instance_id}/payouts/{payout_id}"))
+	require.NoError(t, err)
+
+	require.Equal(t, "payouts.Get", meta.Operation)
+	require.Equal(t, http.StatusOK, meta.StatusCode)
+	require.Equal(t, 2, meta.Attempts)
+	require.Equal(t, "req_000000000000", meta.RequestID)
+	require.JSONEq(t, `{"id":"pa_000000000000"}`, string(meta.Body))
+	require.Positive(t, meta.Duration)
+	require.Equal(t, 100, meta.RateLimit.Limit)
+	require.Equal(t, 99, meta.RateLimit.Remaining)
+	require.False(t, meta.RateLimit.Reset.IsZero())
+}
+
+func TestDo_CapturesResponseMetaOnError(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusNotFound, body: `{"message":"not found","trace_id":"tr_000000000000"}`},
+	}}
+
+	var meta ResponseMeta
+	ctx := WithResponseCapture(context.Background(), &meta)
+
+	_, err := Do[map[string]string](testConfig(transport, nil), ctx, "GET", "/payouts/pa_000000000000", nil)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Equal(t, http.StatusNotFound, meta.StatusCode)
+	require.Equal(t, "tr_000000000000", meta.TraceID)
+	require.Equal(t, 1, meta.Attempts)
+}
+
+func TestDo_CapturesResponseMetaOfConcurrentCalls(t *testing.T) {
+	transport := &countingTransport{body: `{"id":"pa_000000000000"}`}
+
+	var meta ResponseMeta
+	ctx := WithResponseCapture(context.Background(), &meta)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := Do[map[string]string](testConfig(transport, nil), ctx, "GET", "/payouts/pa_000000000000", nil,
+				WithOperation("payouts.Get", "/instances/{instance_id}/payouts/{payout_id}"))
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, "payouts.Get", meta.Operation)
+	require.Equal(t, http.StatusOK, meta.StatusCode)
+	require.Equal(t, 1, meta.Attempts)
+}
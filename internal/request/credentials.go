@@ -0,0 +1,149 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialProvider supplies the API key used to authenticate each request.
+// Implementations must be safe for concurrent use.
+type CredentialProvider interface {
+	// APIKey returns the API key to send with the next request.
+	APIKey(ctx context.Context) (string, error)
+	// Refresh is called once per call when the API rejects the key with 401.
+	// It returns a fresh key, or the rejected key when there is no newer one,
+	// in which case the 401 is returned to the caller.
+	Refresh(ctx context.Context, rejected string) (string, error)
+}
+
+// StaticCredentials is a CredentialProvider that always returns the same key.
+type StaticCredentials string
+
+// APIKey returns the key.
+func (s StaticCredentials) APIKey(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+// Refresh returns the key unchanged.
+func (s StaticCredentials) Refresh(ctx context.Context, rejected string) (string, error) {
+	return string(s), nil
+}
+
+// CredentialFunc is a CredentialProvider that calls the function for every
+// request, and once more on refresh. It suits keys kept in a secret manager
+// that caches them itself.
+type CredentialFunc func(ctx context.Context) (string, error)
+
+// APIKey calls f.
+func (f CredentialFunc) APIKey(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// Refresh calls f.
+func (f CredentialFunc) Refresh(ctx context.Context, rejected string) (string, error) {
+	return f(ctx)
+}
+
+// FileCredentials is a CredentialProvider that reads the API key from a file,
+// such as a mounted Kubernetes secret, and reloads it when the file changes.
+type FileCredentials struct {
+	path     string
+	interval time.Duration
+
+	mu      sync.Mutex
+	key     string
+	modTime time.Time
+	checked time.Time
+}
+
+// NewFileCredentials returns a provider reading the key from path. The file
+// is checked for changes at most once per interval, and on every refresh.
+// Surrounding whitespace is trimmed from its content.
+func NewFileCredentials(path string, interval time.Duration) *FileCredentials {
+	return &FileCredentials{
+		path:     path,
+		interval: interval,
+	}
+}
+
+// APIKey returns the current content of the file.
+func (f *FileCredentials) APIKey(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.key != "" && time.Since(f.checked) < f.interval {
+		return f.key, nil
+	}
+	return f.load(false)
+}
+
+// Refresh reads the file again, regardless of the check interval.
+func (f *FileCredentials) Refresh(ctx context.Context, rejected string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.load(true)
+}
+
+// load reads the key from the file when it changed since the last read, or
+// unconditionally when force is set. It must be called with f.mu held.
+func (f *FileCredentials) load(force bool) (string, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read credentials file: %w", err)
+	}
+	f.checked = time.Now()
+
+	if !force && f.key != "" && info.ModTime().Equal(f.modTime) {
+		return f.key, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("credentials file %s is empty", f.path)
+	}
+
+	f.key = key
+	f.modTime = info.ModTime()
+	return f.key, nil
+}
+
+// apiKey returns the API key to authenticate the next request with.
+func (cfg *Config) apiKey(ctx context.Context) (string, error) {
+	if cfg.Credentials == nil {
+		return cfg.APIKey, nil
+	}
+
+	key, err := cfg.Credentials.APIKey(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get api key: %w", err)
+	}
+	return key, nil
+}
+
+// refreshCredential asks the credential provider for a new key after the
+// current one was rejected, and reports whether the request now carries a
+// different key worth another attempt.
+func refreshCredential(cfg *Config, ctx context.Context, req *Request) bool {
+	if cfg.Credentials == nil {
+		return false
+	}
+
+	rejected := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	key, err := cfg.Credentials.Refresh(ctx, rejected)
+	if err != nil || key == "" || key == rejected {
+		return false
+	}
+
+	req.Header.Set("Authorization", "Bearer "+key)
+	return true
+}
@@ -0,0 +1,95 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rotatingCredentials returns keys in order, moving to the next one on refresh.
+type rotatingCredentials struct {
+	keys    []string
+	current int
+}
+
+func (r *rotatingCredentials) APIKey(ctx context.Context) (string, error) {
+	return r.keys[r.current], nil
+}
+
+func (r *rotatingCredentials) Refresh(ctx context.Context, rejected string) (string, error) {
+	if r.current < len(r.keys)-1 {
+		r.current++
+	}
+	return r.keys[r.current], nil
+}
+
+func TestDo_RefreshesCredentialOn401(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusUnauthorized, body: `{"message":"invalid api key"}`},
+		{status: http.StatusOK, body: `{"id":"pa_000000000000"}`},
+	}}
+
+	cfg := testConfig(transport, nil)
+	cfg.Credentials = &rotatingCredentials{keys: []string{"old_key", "new_key"}}
+
+	result, err := Do[map[string]string](cfg, context.Background(), "POST", "/instances/in_000000000000/payouts/evm", map[string]string{"quote_id": "qu_000000000000"})
+	require.NoError(t, err)
+	require.Equal(t, "pa_000000000000", result["id"])
+	require.Len(t, transport.requests, 2)
+	require.Equal(t, "Bearer old_key", transport.requests[0].Header.Get("Authorization"))
+	require.Equal(t, "Bearer new_key", transport.requests[1].Header.Get("Authorization"))
+}
+
+func TestDo_RefreshesCredentialOnlyOnce(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusUnauthorized, body: `{"message":"invalid api key"}`},
+		{status: http.StatusUnauthorized, body: `{"message":"invalid api key"}`},
+	}}
+
+	cfg := testConfig(transport, fastPolicy())
+	cfg.Credentials = &rotatingCredentials{keys: []string{"old_key", "new_key", "newer_key"}}
+
+	_, err := Do[map[string]string](cfg, context.Background(), "GET", "/instances/in_000000000000/payouts", nil)
+	require.True(t, errors.Is(err, ErrUnauthorized))
+	require.Len(t, transport.requests, 2)
+}
+
+func TestDo_StaticCredentialsDoNotRetry(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusUnauthorized, body: `{"message":"invalid api key"}`},
+	}}
+
+	cfg := testConfig(transport, nil)
+	cfg.Credentials = StaticCredentials("test_key")
+
+	_, err := Do[map[string]string](cfg, context.Background(), "GET", "/instances/in_000000000000/payouts", nil)
+	require.True(t, errors.Is(err, ErrUnauthorized))
+	require.Len(t, transport.requests, 1)
+}
+
+func TestFileCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-key")
+	require.NoError(t, os.WriteFile(path, []byte("old_key\n"), 0o600))
+
+	creds := NewFileCredentials(path, time.Hour)
+
+	key, err := creds.APIKey(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "old_key", key)
+
+	require.NoError(t, os.WriteFile(path, []byte("new_key\n"), 0o600))
+
+	key, err = creds.APIKey(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "old_key", key)
+
+	key, err = creds.Refresh(context.Background(), "old_key")
+	require.NoError(t, err)
+	require.Equal(t, "new_key", key)
+}
@@ -0,0 +1,111 @@
+package request
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/blindpaylabs/blindpay-go/internal/types"
+)
+
+// Environment is the BlindPay environment a client is meant to run against.
+type Environment string
+
+const (
+	EnvironmentSandbox    Environment = "sandbox"
+	EnvironmentProduction Environment = "production"
+)
+
+// ParseEnvironment returns the environment with the given name, ignoring
+// case and surrounding spaces. An empty name yields an empty Environment,
+// which performs no checks.
+func ParseEnvironment(name string) (Environment, error) {
+	env := Environment(strings.ToLower(strings.TrimSpace(name)))
+	if err := env.validate(); err != nil {
+		return "", err
+	}
+	return env, nil
+}
+
+// validate reports an error for an environment other than sandbox, production
+// or empty.
+func (e Environment) validate() error {
+	switch e {
+	case "", EnvironmentSandbox, EnvironmentProduction:
+		return nil
+	}
+	return fmt.Errorf("unknown environment %q, expected %q or %q", string(e), EnvironmentSandbox, EnvironmentProduction)
+}
+
+// CheckNetwork returns a *ParamError when the network cannot be used in the
+// environment: mainnet networks in sandbox and testnet networks in production.
+// Networks the SDK does not know are allowed everywhere.
+func (e Environment) CheckNetwork(field string, network types.Network) error {
+	switch {
+	case e == EnvironmentSandbox && network.IsMainnet():
+		return &ParamError{Param: field, Reason: fmt.Sprintf("%q is a mainnet network and cannot be used in sandbox", network)}
+	case e == EnvironmentProduction && network.IsTestnet():
+		return &ParamError{Param: field, Reason: fmt.Sprintf("%q is a testnet network and cannot be used in production", network)}
+	}
+	return nil
+}
+
+// EnvironmentGuard returns a middleware that rejects calls whose request body
+// carries a network, such as "network" or "receiver_network", that does not
+// belong to env. Rejected calls are never sent and fail with a *ParamError.
+func EnvironmentGuard(env Environment) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if err := checkNetworks(env, req.Body); err != nil {
+				return nil, err
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// checkNetworks checks every network field of body, at any depth.
+func checkNetworks(env Environment, body any) error {
+	switch body.(type) {
+	case nil, *RawBody:
+		return nil
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil
+	}
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil
+	}
+	return checkNetworkValue(env, v)
+}
+
+// checkNetworkValue walks a decoded JSON value and checks the string values of
+// fields named "network" or ending in "_network".
+func checkNetworkValue(env Environment, v any) error {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if network, ok := child.(string); ok && (k == "network" || strings.HasSuffix(k, "_network")) {
+				if err := env.CheckNetwork(k, types.Network(network)); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := checkNetworkValue(env, child); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, child := range val {
+			if err := checkNetworkValue(env, child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironmentGuard(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     Environment
+		body    any
+		wantErr bool
+	}{
+		{name: "sandbox testnet", env: EnvironmentSandbox, body: map[string]any{"network": "base_sepolia"}},
+		{name: "sandbox mainnet", env: EnvironmentSandbox, body: map[string]any{"network": "base"}, wantErr: true},
+		{name: "production mainnet", env: EnvironmentProduction, body: map[string]any{"network": "solana"}},
+		{name: "production testnet", env: EnvironmentProduction, body: map[string]any{"network": "solana_devnet"}, wantErr: true},
+		{name: "nested receiver network", env: EnvironmentProduction, body: map[string]any{"transfer": map[string]any{"receiver_network": "polygon_amoy"}}, wantErr: true},
+		{name: "unknown network", env: EnvironmentProduction, body: map[string]any{"network": "unknown"}},
+		{name: "no body", env: EnvironmentSandbox},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &sequenceTransport{responses: []response{{status: http.StatusOK, body: `{}`}}}
+
+			cfg := testConfig(transport, nil)
+			cfg.Middleware = []Middleware{EnvironmentGuard(tt.env)}
+
+			_, err := Do[map[string]any](cfg, context.Background(), "POST", "/instances/in_000000000000/wallets", tt.body)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, errors.Is(err, ErrInvalidParams))
+				require.Empty(t, transport.requests)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, transport.requests, 1)
+		})
+	}
+}
+
+func TestParseEnvironment(t *testing.T) {
+	env, err := ParseEnvironment(" Production ")
+	require.NoError(t, err)
+	require.Equal(t, EnvironmentProduction, env)
+
+	env, err = ParseEnvironment("")
+	require.NoError(t, err)
+	require.Empty(t, env)
+
+	_, err = ParseEnvironment("staging")
+	require.Error(t, err)
+}
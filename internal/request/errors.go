@@ -0,0 +1,195 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors matched by errors.Is against an *APIError.
+var (
+	ErrNotFound            = errors.New("blindpay: not found")
+	ErrUnauthorized        = errors.New("blindpay: unauthorized")
+	ErrForbidden           = errors.New("blindpay: forbidden")
+	ErrRateLimited         = errors.New("blindpay: rate limited")
+	ErrValidation          = errors.New("blindpay: validation failed")
+	ErrConflict            = errors.New("blindpay: conflict")
+	ErrQuoteExpired        = errors.New("blindpay: quote expired")
+	ErrInsufficientBalance = errors.New("blindpay: insufficient balance")
+)
+
+// ErrInvalidParams is matched by errors.Is when the SDK rejects a call before
+// sending it, for example because a required ID is empty.
+var ErrInvalidParams = errors.New("blindpay: invalid params")
+
+// ErrTransport is matched by errors.Is when a request could not be sent or
+// its response could not be read.
+var ErrTransport = errors.New("blindpay: transport error")
+
+// ErrorCode is the machine-readable code of an ErrorItem, as returned by
+// ErrorItem.ErrorCode.
+type ErrorCode string
+
+// Error codes returned by the BlindPay API.
+const (
+	ErrorCodeNotFound            ErrorCode = "not_found"
+	ErrorCodeUnauthorized        ErrorCode = "unauthorized"
+	ErrorCodeForbidden           ErrorCode = "forbidden"
+	ErrorCodeRateLimited         ErrorCode = "rate_limited"
+	ErrorCodeValidation          ErrorCode = "validation_error"
+	ErrorCodeConflict            ErrorCode = "conflict"
+	ErrorCodeQuoteExpired        ErrorCode = "quote_expired"
+	ErrorCodeInsufficientBalance ErrorCode = "insufficient_balance"
+)
+
+// sentinel returns the sentinel error that corresponds to the code, if any.
+func (c ErrorCode) sentinel() error {
+	switch c {
+	case ErrorCodeNotFound:
+		return ErrNotFound
+	case ErrorCodeUnauthorized:
+		return ErrUnauthorized
+	case ErrorCodeForbidden:
+		return ErrForbidden
+	case ErrorCodeRateLimited:
+		return ErrRateLimited
+	case ErrorCodeValidation:
+		return ErrValidation
+	case ErrorCodeConflict:
+		return ErrConflict
+	case ErrorCodeQuoteExpired:
+		return ErrQuoteExpired
+	case ErrorCodeInsufficientBalance:
+		return ErrInsufficientBalance
+	}
+	return nil
+}
+
+// ErrorCode returns the code of the item as an ErrorCode.
+func (i ErrorItem) ErrorCode() ErrorCode {
+	return ErrorCode(i.Code)
+}
+
+// HasCode reports whether any item of the error carries the given code.
+func (e *APIError) HasCode(code ErrorCode) bool {
+	for _, item := range e.Errors {
+		if item.ErrorCode() == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Is makes errors.Is match an APIError against the sentinel errors, based on
+// its status code and its error codes. Conditions that share a status code
+// with others, such as ErrQuoteExpired, are only matched by their code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		if e.StatusCode == http.StatusNotFound {
+			return true
+		}
+	case ErrUnauthorized:
+		if e.StatusCode == http.StatusUnauthorized {
+			return true
+		}
+	case ErrForbidden:
+		if e.StatusCode == http.StatusForbidden {
+			return true
+		}
+	case ErrRateLimited:
+		if e.StatusCode == http.StatusTooManyRequests {
+			return true
+		}
+	case ErrValidation:
+		if e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity {
+			return true
+		}
+	case ErrConflict:
+		if e.StatusCode == http.StatusConflict {
+			return true
+		}
+	case ErrQuoteExpired, ErrInsufficientBalance:
+		// Matched by code only.
+	default:
+		return false
+	}
+
+	for _, item := range e.Errors {
+		if item.ErrorCode().sentinel() == target {
+			return true
+		}
+	}
+	return false
+}
+
+// ParamError reports a call rejected by the SDK before it was sent because a
+// parameter is missing or invalid. It matches ErrInvalidParams.
+type ParamError struct {
+	// Param is the name of the offending parameter, such as "receiver ID".
+	Param string
+	// Reason describes what is wrong with it, such as "cannot be empty".
+	Reason string
+}
+
+// Error implements the error interface for ParamError.
+func (e *ParamError) Error() string {
+	return e.Param + " " + e.Reason
+}
+
+// Is makes errors.Is(err, ErrInvalidParams) match a ParamError.
+func (e *ParamError) Is(target error) bool {
+	return target == ErrInvalidParams
+}
+
+// NilParam returns the error for a required parameter that is nil.
+func NilParam(name string) error {
+	return &ParamError{Param: name, Reason: "cannot be nil"}
+}
+
+// EmptyParam returns the error for a required parameter that is empty.
+func EmptyParam(name string) error {
+	return &ParamError{Param: name, Reason: "cannot be empty"}
+}
+
+// TransportError reports a request that could not be sent or whose response
+// could not be read. It matches ErrTransport and unwraps to the underlying error.
+type TransportError struct {
+	// Op is the step that failed: "create request", "send request" or "read response".
+	Op  string
+	Err error
+}
+
+// Error implements the error interface for TransportError.
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("failed to %s: %v", e.Op, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, ErrTransport) match a TransportError.
+func (e *TransportError) Is(target error) bool {
+	return target == ErrTransport
+}
+
+// CreateError reports a failed create call together with the Idempotency-Key
+// it sent, so that the call can be retried without creating twice when it
+// failed after the API acted on it, as on a timeout. It unwraps to the error
+// of the call.
+type CreateError struct {
+	IdempotencyKey string
+	Err            error
+}
+
+// Error implements the error interface for CreateError.
+func (e *CreateError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error of the call.
+func (e *CreateError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,76 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    *APIError
+		target error
+	}{
+		{"not found", &APIError{StatusCode: http.StatusNotFound}, ErrNotFound},
+		{"unauthorized", &APIError{StatusCode: http.StatusUnauthorized}, ErrUnauthorized},
+		{"forbidden", &APIError{StatusCode: http.StatusForbidden}, ErrForbidden},
+		{"rate limited", &APIError{StatusCode: http.StatusTooManyRequests}, ErrRateLimited},
+		{"bad request", &APIError{StatusCode: http.StatusBadRequest}, ErrValidation},
+		{"unprocessable", &APIError{StatusCode: http.StatusUnprocessableEntity}, ErrValidation},
+		{"conflict", &APIError{StatusCode: http.StatusConflict}, ErrConflict},
+		{"quote expired by code", &APIError{StatusCode: http.StatusBadRequest, Errors: []ErrorItem{{Code: "quote_expired", Message: "invalid"}}}, ErrQuoteExpired},
+		{"insufficient balance", &APIError{StatusCode: http.StatusBadRequest, Errors: []ErrorItem{{Code: "insufficient_balance"}}}, ErrInsufficientBalance},
+		{"code overrides status", &APIError{StatusCode: http.StatusBadRequest, Errors: []ErrorItem{{Code: "conflict"}}}, ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", tt.err)
+			require.ErrorIs(t, err, tt.target)
+		})
+	}
+
+	require.NotErrorIs(t, &APIError{StatusCode: http.StatusBadRequest}, ErrNotFound)
+	require.NotErrorIs(t, &APIError{StatusCode: http.StatusBadRequest, Message: "Quote has expired"}, ErrQuoteExpired)
+	require.NotErrorIs(t, &APIError{StatusCode: http.StatusBadRequest, Errors: []ErrorItem{{Message: "Insufficient balance"}}}, ErrInsufficientBalance)
+	require.NotErrorIs(t, &APIError{StatusCode: http.StatusNotFound}, ErrTransport)
+}
+
+func TestAPIError_HasCode(t *testing.T) {
+	err := parseAPIError(http.StatusBadRequest, []byte(`{"message":"failed","errors":[{"code":"insufficient_balance","message":"no funds"}]}`))
+	require.True(t, err.HasCode(ErrorCodeInsufficientBalance))
+	require.False(t, err.HasCode(ErrorCodeQuoteExpired))
+	require.Equal(t, ErrorCodeInsufficientBalance, err.Errors[0].ErrorCode())
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+}
+
+func TestParamError(t *testing.T) {
+	err := EmptyParam("receiver ID")
+	require.EqualError(t, err, "receiver ID cannot be empty")
+	require.ErrorIs(t, err, ErrInvalidParams)
+
+	var paramErr *ParamError
+	require.ErrorAs(t, NilParam("params"), &paramErr)
+	require.Equal(t, "params", paramErr.Param)
+}
+
+type failingTransport struct{ err error }
+
+func (f failingTransport) RoundTrip(*http.Request) (*http.Response, error) { return nil, f.err }
+
+func TestDo_TransportError(t *testing.T) {
+	cause := errors.New("connection refused")
+
+	_, err := Do[map[string]string](testConfig(failingTransport{cause}, nil), context.Background(), "GET", "/payouts", nil)
+	require.ErrorIs(t, err, ErrTransport)
+	require.ErrorIs(t, err, cause)
+
+	var transportErr *TransportError
+	require.ErrorAs(t, err, &transportErr)
+	require.Equal(t, "send request", transportErr.Op)
+}
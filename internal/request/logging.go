@@ -0,0 +1,197 @@
+package request
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// redacted replaces sensitive values in log records.
+const redacted = "[REDACTED]"
+
+// sensitiveFields lists the JSON fields whose values are never logged.
+var sensitiveFields = map[string]bool{
+	"tax_id":                    true,
+	"date_of_birth":             true,
+	"account_number":            true,
+	"pix_key":                   true,
+	"spei_clabe":                true,
+	"swift_account_number_iban": true,
+	"swift_intermediary_bank_account_number_iban": true,
+	"sender_tax_id":         true,
+	"sender_account_number": true,
+	"ach_cop_bank_account":  true,
+	"ach_cop_document_id":   true,
+	"transfers_account":     true,
+	"ted_cpf_cnpj":          true,
+	"pix_safe_cpf_cnpj":     true,
+}
+
+// sensitiveHeaders lists the request headers whose values are never logged.
+var sensitiveHeaders = []string{"Authorization"}
+
+// Logging returns a middleware that writes one record per API call to logger.
+//
+// Records carry the operation, method, route, status, latency, attempts and
+// the BlindPay trace ID of failed calls. API errors are logged by their
+// status and error codes only, since their message may quote the response
+// body. Request and response bodies are only included when the logger is
+// enabled for debug level, with sensitive fields redacted.
+func Logging(logger *slog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			start := time.Now()
+			resp, err := next(ctx, req)
+			latency := time.Since(start)
+
+			level := slog.LevelInfo
+			attrs := []slog.Attr{
+				slog.String("operation", req.Operation),
+				slog.String("method", req.Method),
+				slog.String("route", routeOf(req)),
+				slog.String("instance_id", req.InstanceID),
+				slog.Duration("latency", latency),
+			}
+
+			status := 0
+			if resp != nil {
+				status = resp.StatusCode
+				if status != 0 {
+					attrs = append(attrs, slog.Int("status", status))
+				}
+				attrs = append(attrs, slog.Int("attempts", resp.Attempts))
+			}
+
+			if err != nil {
+				level = slog.LevelError
+
+				var apiErr *APIError
+				if errors.As(err, &apiErr) {
+					if apiErr.StatusCode < 500 {
+						level = slog.LevelWarn
+					}
+					if status == 0 {
+						attrs = append(attrs, slog.Int("status", apiErr.StatusCode))
+					}
+					if codes := errorCodes(apiErr); len(codes) > 0 {
+						attrs = append(attrs, slog.Any("error_codes", codes))
+					}
+					if apiErr.TraceID != "" {
+						attrs = append(attrs, slog.String("trace_id", apiErr.TraceID))
+					}
+				} else {
+					attrs = append(attrs, slog.String("error", err.Error()))
+				}
+			}
+
+			if logger.Enabled(ctx, slog.LevelDebug) {
+				attrs = append(attrs,
+					slog.Any("request_header", redactHeader(req.Header)),
+					slog.String("request_body", redactRequestBody(req.Body)),
+				)
+				if resp != nil && resp.Body != nil {
+					attrs = append(attrs, slog.String("response_body", redactJSON(resp.Body)))
+				}
+			}
+
+			logger.LogAttrs(ctx, level, "blindpay request", attrs...)
+
+			return resp, err
+		}
+	}
+}
+
+// errorCodes returns the codes of the items of an API error, which unlike
+// their messages never carry request data.
+func errorCodes(err *APIError) []string {
+	var codes []string
+	for _, item := range err.Errors {
+		if item.Code != "" {
+			codes = append(codes, item.Code)
+		}
+	}
+	return codes
+}
+
+// routeOf returns the path template of the request, falling back to the path
+// without its query string when the operation has no route.
+func routeOf(req *Request) string {
+	if req.Route != "" {
+		return req.Route
+	}
+	path, _, _ := strings.Cut(req.Path, "?")
+	return path
+}
+
+// redactHeader returns a copy of header with sensitive values masked.
+func redactHeader(header http.Header) http.Header {
+	h := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if h.Get(name) != "" {
+			h.Set(name, redacted)
+		}
+	}
+	return h
+}
+
+// redactRequestBody renders a request body for logging with sensitive fields redacted.
+func redactRequestBody(body any) string {
+	switch b := body.(type) {
+	case nil:
+		return ""
+	case *RawBody:
+		return fmt.Sprintf("[%s, %d bytes]", b.ContentType, len(b.Data))
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return ""
+	}
+	return redactJSON(data)
+}
+
+// redactJSON returns data with the values of sensitive fields replaced, at any
+// depth. Data that is not valid JSON is not returned, since it cannot be
+// checked for sensitive values.
+func redactJSON(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Sprintf("[non-JSON body, %d bytes]", len(data))
+	}
+
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// redactValue walks a decoded JSON value and masks sensitive fields.
+func redactValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if sensitiveFields[k] {
+				if child != nil {
+					val[k] = redacted
+				}
+				continue
+			}
+			val[k] = redactValue(child)
+		}
+	case []any:
+		for i, child := range val {
+			val[i] = redactValue(child)
+		}
+	}
+	return v
+}
@@ -0,0 +1,128 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogging_InfoRecord(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusOK, body: `{"id":"re_000000000000","tax_id":"12345678900"}`},
+	}}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	cfg := testConfig(transport, nil)
+	cfg.InstanceID = "in_000000000000"
+	cfg.Middleware = []Middleware{Logging(logger)}
+
+	_, err := Do[map[string]string](cfg, context.Background(), "GET", "/instances/in_000000000000/receivers/re_000000000000", nil,
+		WithOperation("receivers.Get", "/instances/{instance_id}/receivers/{receiver_id}"))
+	require.NoError(t, err)
+
+	records := decodeRecords(t, &buf)
+	require.Len(t, records, 1)
+
+	record := records[0]
+	require.Equal(t, "INFO", record["level"])
+	require.Equal(t, "receivers.Get", record["operation"])
+	require.Equal(t, "GET", record["method"])
+	require.Equal(t, "/instances/{instance_id}/receivers/{receiver_id}", record["route"])
+	require.Equal(t, float64(http.StatusOK), record["status"])
+	require.Equal(t, float64(1), record["attempts"])
+	require.Contains(t, record, "latency")
+	require.NotContains(t, record, "response_body")
+	require.NotContains(t, buf.String(), "12345678900")
+}
+
+func TestLogging_DebugRecordRedactsSecrets(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusBadRequest, body: `{"message":"invalid","trace_id":"tr_000000000000","errors":[{"message":"bad","account_number":"987654321"}]}`},
+	}}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	cfg := testConfig(transport, nil)
+	cfg.Middleware = []Middleware{Logging(logger)}
+
+	body := map[string]any{
+		"name":        "Account",
+		"pix_key":     "john@example.com",
+		"spei_clabe":  "012345678901234567",
+		"owners":      []any{map[string]any{"first_name": "John", "date_of_birth": "1990-01-01", "tax_id": "123"}},
+		"description": nil,
+	}
+
+	_, err := Do[map[string]string](cfg, context.Background(), "POST", "/instances/in_000000000000/receivers?x=1", body)
+	require.Error(t, err)
+
+	records := decodeRecords(t, &buf)
+	require.Len(t, records, 1)
+
+	record := records[0]
+	require.Equal(t, "WARN", record["level"])
+	require.Equal(t, "/instances/in_000000000000/receivers", record["route"])
+	require.Equal(t, "tr_000000000000", record["trace_id"])
+
+	logged := buf.String()
+	for _, secret := range []string{"test_key", "john@example.com", "012345678901234567", "1990-01-01", "987654321"} {
+		require.NotContains(t, logged, secret)
+	}
+	require.Contains(t, record["request_body"], `"first_name":"John"`)
+	require.Contains(t, record["request_body"], `"pix_key":"[REDACTED]"`)
+	require.Contains(t, record["response_body"], `"account_number":"[REDACTED]"`)
+
+	header := record["request_header"].(map[string]any)
+	require.Equal(t, []any{"[REDACTED]"}, header["Authorization"])
+}
+
+func TestLogging_APIErrorOmitsMessage(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusConflict, body: `{"message":"receiver john@example.com already exists","trace_id":"tr_000000000000","errors":[{"code":"conflict","message":"tax_id 12345678900 is taken"}]}`},
+		{status: http.StatusBadGateway, body: `<html>upstream failed for john@example.com</html>`},
+	}}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	cfg := testConfig(transport, nil)
+	cfg.Middleware = []Middleware{Logging(logger)}
+
+	for range transport.responses {
+		_, err := Do[map[string]string](cfg, context.Background(), "GET", "/instances/in_000000000000/receivers", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "john@example.com")
+	}
+
+	records := decodeRecords(t, &buf)
+	require.Len(t, records, 2)
+
+	require.Equal(t, float64(http.StatusConflict), records[0]["status"])
+	require.Equal(t, []any{"conflict"}, records[0]["error_codes"])
+	require.Equal(t, "tr_000000000000", records[0]["trace_id"])
+	require.Equal(t, float64(http.StatusBadGateway), records[1]["status"])
+
+	logged := buf.String()
+	for _, secret := range []string{"john@example.com", "12345678900"} {
+		require.NotContains(t, logged, secret)
+	}
+}
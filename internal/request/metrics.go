@@ -0,0 +1,37 @@
+package request
+
+import (
+	"fmt"
+	"time"
+)
+
+// Metrics receives measurements about API calls.
+//
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveRequest records a completed API call. statusClass is "2xx",
+	// "4xx", "5xx" and so on, or "error" when no response was received.
+	ObserveRequest(operation, statusClass string, duration time.Duration)
+	// ObserveRetry records a retry of an API call after the given status,
+	// which is 0 for transport errors.
+	ObserveRetry(operation string, status int)
+	// ObserveRateLimited records a 429 response received by an API call.
+	ObserveRateLimited(operation string)
+}
+
+// StatusClass returns the class of an HTTP status code, such as "2xx", or
+// "error" when no status was received.
+func StatusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "error"
+	}
+	return fmt.Sprintf("%dxx", status/100)
+}
+
+// metricsName returns the operation name reported to metrics.
+func metricsName(req *Request) string {
+	if req.Operation != "" {
+		return req.Operation
+	}
+	return req.Method + " " + routeOf(req)
+}
@@ -0,0 +1,87 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Request describes a logical API call as seen by middleware.
+//
+// Middleware may modify the request before passing it on, for example to
+// add headers or to replace the body.
+type Request struct {
+	// Operation is the logical SDK operation, e.g. "payouts.CreateEvm".
+	Operation string
+	// Route is the path template of the operation, e.g.
+	// "/instances/{instance_id}/payouts/{payout_id}".
+	Route string
+	// InstanceID is the BlindPay instance the call targets.
+	InstanceID string
+	Method     string
+	// Path is the request path, including the query string.
+	Path string
+	// Params holds the typed params the operation was called with.
+	Params any
+	// Body is the value sent as the request body, or nil.
+	Body   any
+	Header http.Header
+}
+
+// Response describes the outcome of a logical API call as seen by middleware.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	// Body holds the raw response body.
+	Body []byte
+	// Result holds the decoded response body. It is nil for error responses.
+	Result any
+	// Attempts is the number of HTTP attempts made, including retries.
+	Attempts int
+	// Cached reports whether the response was served from the response cache.
+	Cached bool
+}
+
+// Handler performs a logical API call.
+//
+// Error responses from the API are returned as *APIError alongside the Response.
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Middleware wraps a Handler to add behavior around every API call.
+type Middleware func(next Handler) Handler
+
+// chain wraps h with the given middleware. The first middleware is the outermost.
+func chain(middleware []Middleware, h Handler) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] != nil {
+			h = middleware[i](h)
+		}
+	}
+	return h
+}
+
+// ResourceID returns the ID of the resource the call targets: the path value
+// matching the last placeholder of the route, such as the payout ID of
+// "/instances/{instance_id}/payouts/{payout_id}". It returns an empty string
+// when the route has no resource placeholder.
+func (r *Request) ResourceID() string {
+	path, _, _ := strings.Cut(r.Path, "?")
+
+	routeSegments := strings.Split(r.Route, "/")
+	pathSegments := strings.Split(path, "/")
+	if len(routeSegments) != len(pathSegments) {
+		return ""
+	}
+
+	for i := len(routeSegments) - 1; i >= 0; i-- {
+		segment := routeSegments[i]
+		if segment == "{instance_id}" {
+			return ""
+		}
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			return pathSegments[i]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,131 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type createParams struct {
+	ReceiverID string `json:"-"`
+	Name       string `json:"name"`
+}
+
+func TestDo_MiddlewareOrderAndRequestInfo(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusOK, body: `{"id":"ba_000000000000"}`},
+	}}
+
+	var calls []string
+	var seen *Request
+	var result any
+
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				calls = append(calls, name+":before")
+				resp, err := next(ctx, req)
+				calls = append(calls, name+":after")
+				return resp, err
+			}
+		}
+	}
+
+	inspect := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			seen = req
+			req.Header.Set("X-Audit", "1")
+			resp, err := next(ctx, req)
+			if resp != nil {
+				result = resp.Result
+			}
+			return resp, err
+		}
+	}
+
+	cfg := testConfig(transport, nil)
+	cfg.InstanceID = "in_000000000000"
+	cfg.Middleware = []Middleware{record("outer"), record("inner"), inspect}
+
+	params := &createParams{ReceiverID: "re_000000000000", Name: "Account"}
+	out, err := Do[map[string]string](cfg, context.Background(), "POST", "/instances/in_000000000000/receivers/re_000000000000/bank-accounts",
+		map[string]any{"name": params.Name},
+		WithOperation("bankaccounts.CreatePix", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts"),
+		WithParams(params))
+	require.NoError(t, err)
+	require.Equal(t, "ba_000000000000", out["id"])
+
+	require.Equal(t, []string{"outer:before", "inner:before", "inner:after", "outer:after"}, calls)
+	require.Equal(t, "bankaccounts.CreatePix", seen.Operation)
+	require.Equal(t, "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts", seen.Route)
+	require.Equal(t, "in_000000000000", seen.InstanceID)
+	require.Same(t, params, seen.Params)
+	require.Equal(t, map[string]string{"id": "ba_000000000000"}, result)
+	require.Equal(t, "1", transport.requests[0].Header.Get("X-Audit"))
+}
+
+func TestDo_MiddlewareSeesAPIError(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusNotFound, body: `{"message":"not found","trace_id":"tr_1"}`},
+	}}
+
+	var seenErr error
+	var seenStatus int
+
+	cfg := testConfig(transport, nil)
+	cfg.Middleware = []Middleware{func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			resp, err := next(ctx, req)
+			seenErr = err
+			seenStatus = resp.StatusCode
+			return resp, err
+		}
+	}}
+
+	_, err := Do[map[string]string](cfg, context.Background(), "GET", "/payouts/pa_1", nil)
+	require.Error(t, err)
+
+	var apiErr *APIError
+	require.True(t, errors.As(seenErr, &apiErr))
+	require.Equal(t, "tr_1", apiErr.TraceID)
+	require.Equal(t, http.StatusNotFound, seenStatus)
+}
+
+func TestDo_MiddlewareCanShortCircuit(t *testing.T) {
+	transport := &sequenceTransport{}
+
+	cfg := testConfig(transport, nil)
+	cfg.Middleware = []Middleware{func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			return &Response{StatusCode: http.StatusOK, Result: map[string]string{"id": "cached"}}, nil
+		}
+	}}
+
+	out, err := Do[map[string]string](cfg, context.Background(), "GET", "/payouts", nil)
+	require.NoError(t, err)
+	require.Equal(t, "cached", out["id"])
+	require.Empty(t, transport.requests)
+}
+
+func TestRequest_ResourceID(t *testing.T) {
+	tests := []struct {
+		route string
+		path  string
+		want  string
+	}{
+		{"/instances/{instance_id}/payouts/{payout_id}", "/instances/in_1/payouts/pa_1", "pa_1"},
+		{"/instances/{instance_id}/receivers/{receiver_id}/bank-accounts/{id}", "/instances/in_1/receivers/re_1/bank-accounts/ba_1", "ba_1"},
+		{"/instances/{instance_id}/receivers/{receiver_id}/bank-accounts", "/instances/in_1/receivers/re_1/bank-accounts?type=pix", "re_1"},
+		{"/instances/{instance_id}/payouts", "/instances/in_1/payouts?limit=10", ""},
+		{"/e/payouts/{payout_id}", "/e/payouts/pa_1", "pa_1"},
+		{"", "/instances/in_1/payouts/pa_1", ""},
+	}
+
+	for _, tt := range tests {
+		req := &Request{Route: tt.route, Path: tt.path}
+		require.Equal(t, tt.want, req.ResourceID(), tt.route)
+	}
+}
@@ -0,0 +1,110 @@
+package request
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Option configures a single call made with Do.
+type Option func(*call)
+
+// call holds the per-call settings applied by Options.
+type call struct {
+	operation string
+	route     string
+	params    any
+	header    http.Header
+	stream    func(io.Reader) (bool, error)
+
+	idempotent     bool
+	idempotencyKey string
+	sentKey        *string
+}
+
+// newCall applies the given options on top of the defaults.
+func newCall(opts []Option) *call {
+	c := &call{
+		header: http.Header{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithOperation names the logical operation performed by the call and its path template.
+func WithOperation(name, route string) Option {
+	return func(c *call) {
+		c.operation = name
+		c.route = route
+	}
+}
+
+// WithParams exposes the typed params of the call to middleware when they
+// differ from the request body.
+func WithParams(params any) Option {
+	return func(c *call) {
+		c.params = params
+	}
+}
+
+// WithIdempotency makes the call send an Idempotency-Key header, as create
+// methods do: key when set, else the per-call idempotency key carried by the
+// context if no other call used it, else a generated one. The key sent is stored in *sent, so that the
+// method can report it to its caller. The same key is sent on every retry of
+// the call, which also makes the call eligible for retries regardless of its
+// HTTP method. When the call fails, its error is a *CreateError carrying the
+// key.
+func WithIdempotency(key string, sent *string) Option {
+	return func(c *call) {
+		c.idempotent = true
+		c.idempotencyKey = key
+		c.sentKey = sent
+	}
+}
+
+// setIdempotencyKey sets the Idempotency-Key header of a call made with
+// WithIdempotency.
+func (c *call) setIdempotencyKey(ctx context.Context, method string) {
+	if !c.idempotent {
+		return
+	}
+
+	key := c.idempotencyKey
+	if key == "" {
+		key = callOptionsFrom(ctx).takeIdempotencyKey(method)
+	}
+	if key == "" {
+		key = NewIdempotencyKey()
+	}
+
+	c.header.Set("Idempotency-Key", key)
+	if c.sentKey != nil {
+		*c.sentKey = key
+	}
+}
+
+// createError wraps err in a *CreateError carrying the Idempotency-Key of a
+// call made with WithIdempotency, and returns other errors as is.
+func (c *call) createError(err error) error {
+	if !c.idempotent {
+		return err
+	}
+	return &CreateError{IdempotencyKey: c.header.Get("Idempotency-Key"), Err: err}
+}
+
+// NewIdempotencyKey returns a random (version 4) UUID suitable as an idempotency key.
+func NewIdempotencyKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("blindpay: failed to generate idempotency key: %v", err))
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,256 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit configures the client-side token buckets that pace API calls.
+//
+// Reads (GET requests) and writes (every other method) draw from separate
+// buckets so that a burst of list calls cannot starve payouts and other
+// writes sharing the same API key. A rate of zero leaves that class unlimited.
+type RateLimit struct {
+	// ReadsPerSecond is the sustained rate of GET requests.
+	ReadsPerSecond float64
+	// ReadBurst is the number of GET requests that may be sent at once.
+	// It defaults to 1.
+	ReadBurst int
+	// WritesPerSecond is the sustained rate of POST, PUT, PATCH and DELETE requests.
+	WritesPerSecond float64
+	// WriteBurst is the number of writes that may be sent at once.
+	// It defaults to 1.
+	WriteBurst int
+	// RecoveryInterval is how often a bucket that was slowed down by a 429
+	// response regains a tenth of its configured rate. It defaults to 5 seconds.
+	RecoveryInterval time.Duration
+}
+
+const (
+	// minRateFraction is the lowest fraction of the configured rate a bucket
+	// slows down to after repeated 429 responses.
+	minRateFraction = 0.1
+	// defaultRecoveryInterval is used when RateLimit.RecoveryInterval is zero.
+	defaultRecoveryInterval = 5 * time.Second
+)
+
+// RateLimiter paces requests with one token bucket for reads and one for
+// writes. It adapts to the server: a 429 response halves the rate of the
+// bucket that received it, Retry-After and exhausted X-RateLimit-* headers
+// pause it, and the rate then recovers gradually.
+//
+// A RateLimiter is safe for concurrent use and is meant to be shared by every
+// sub-client of a BlindPay client.
+type RateLimiter struct {
+	read  *bucket
+	write *bucket
+}
+
+// NewRateLimiter creates a limiter from the given limits.
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	recovery := limit.RecoveryInterval
+	if recovery <= 0 {
+		recovery = defaultRecoveryInterval
+	}
+
+	return &RateLimiter{
+		read:  newBucket(limit.ReadsPerSecond, limit.ReadBurst, recovery),
+		write: newBucket(limit.WritesPerSecond, limit.WriteBurst, recovery),
+	}
+}
+
+// bucketFor returns the bucket used for the given HTTP method, or nil when
+// that class of requests is not limited.
+func (l *RateLimiter) bucketFor(method string) *bucket {
+	if l == nil {
+		return nil
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return l.read
+	}
+	return l.write
+}
+
+// wait blocks until a request with the given method may be sent.
+func (l *RateLimiter) wait(ctx context.Context, method string) error {
+	return l.bucketFor(method).wait(ctx)
+}
+
+// observe adapts the limiter to a response received for the given method.
+func (l *RateLimiter) observe(method string, status int, header http.Header) {
+	l.bucketFor(method).observe(status, header)
+}
+
+// bucket is a token bucket whose rate can be lowered and recovered.
+type bucket struct {
+	mu sync.Mutex
+
+	limit    float64
+	rate     float64
+	burst    float64
+	tokens   float64
+	recovery time.Duration
+
+	last        time.Time
+	lastAdjust  time.Time
+	pausedUntil time.Time
+
+	now func() time.Time
+}
+
+func newBucket(rate float64, burst int, recovery time.Duration) *bucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &bucket{
+		limit:    rate,
+		rate:     rate,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		recovery: recovery,
+		now:      time.Now,
+	}
+}
+
+// advance refills tokens and recovers the rate up to now. The caller must hold mu.
+func (b *bucket) advance(now time.Time) {
+	if b.last.IsZero() {
+		b.last = now
+	}
+
+	if b.rate < b.limit && !b.lastAdjust.IsZero() {
+		if steps := int(now.Sub(b.lastAdjust) / b.recovery); steps > 0 {
+			b.rate = math.Min(b.limit, b.rate+float64(steps)*b.limit*minRateFraction)
+			b.lastAdjust = b.lastAdjust.Add(time.Duration(steps) * b.recovery)
+		}
+	}
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// reserve takes a token and returns how long the caller must wait before using it.
+func (b *bucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.advance(now)
+
+	b.tokens--
+
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	if pause := b.pausedUntil.Sub(now); pause > delay {
+		delay = pause
+	}
+
+	return delay
+}
+
+// cancel returns a token taken by reserve that will not be used.
+func (b *bucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
+
+func (b *bucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	delay := b.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	if !sleep(ctx, delay) {
+		b.cancel()
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("rate limiter: %w", err)
+		}
+		return fmt.Errorf("rate limiter: waiting %s would exceed the context deadline: %w", delay, context.DeadlineExceeded)
+	}
+
+	return nil
+}
+
+func (b *bucket) observe(status int, header http.Header) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.advance(now)
+
+	if status == http.StatusTooManyRequests {
+		b.rate = math.Max(b.rate/2, b.limit*minRateFraction)
+		b.tokens = math.Min(b.tokens, 0)
+		b.lastAdjust = now
+
+		if d, ok := parseRetryAfter(header.Get("Retry-After")); ok {
+			b.pause(now.Add(d))
+		}
+	}
+
+	if reset, ok := rateLimitExhausted(header, now); ok {
+		b.pause(reset)
+	}
+}
+
+// pause stops the bucket from handing out tokens until the given time. The
+// caller must hold mu.
+func (b *bucket) pause(until time.Time) {
+	if until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+// rateLimitExhausted reports whether the X-RateLimit-Remaining header says no
+// request is left in the current window, and when that window resets.
+func rateLimitExhausted(header http.Header, now time.Time) (time.Time, bool) {
+	if header == nil {
+		return time.Time{}, false
+	}
+
+	remaining, err := strconv.ParseFloat(header.Get("X-RateLimit-Remaining"), 64)
+	if err != nil || remaining > 0 {
+		return time.Time{}, false
+	}
+
+	return parseRateLimitReset(header.Get("X-RateLimit-Reset"), now)
+}
+
+// parseRateLimitReset parses an X-RateLimit-Reset header given either as a
+// number of seconds from now or as a Unix timestamp.
+func parseRateLimitReset(value string, now time.Time) (time.Time, bool) {
+	reset, err := strconv.ParseFloat(value, 64)
+	if err != nil || reset < 0 {
+		return time.Time{}, false
+	}
+
+	// Values this large can only be Unix timestamps.
+	if reset > 1e9 {
+		return time.Unix(0, int64(reset*float64(time.Second))), true
+	}
+
+	return now.Add(time.Duration(reset * float64(time.Second))), true
+}
@@ -0,0 +1,144 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock returns a bucket clock that only moves when advanced.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func testLimiter(limit RateLimit) (*RateLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	l := NewRateLimiter(limit)
+	for _, b := range []*bucket{l.read, l.write} {
+		if b != nil {
+			b.now = clock.Now
+		}
+	}
+	return l, clock
+}
+
+func TestRateLimiter_Burst(t *testing.T) {
+	l, clock := testLimiter(RateLimit{ReadsPerSecond: 2, ReadBurst: 2})
+
+	require.Zero(t, l.read.reserve())
+	require.Zero(t, l.read.reserve())
+	require.Equal(t, 500*time.Millisecond, l.read.reserve())
+
+	clock.Advance(time.Second)
+	require.Zero(t, l.read.reserve())
+}
+
+func TestRateLimiter_SeparateBudgets(t *testing.T) {
+	l, _ := testLimiter(RateLimit{ReadsPerSecond: 1, WritesPerSecond: 1})
+
+	require.Zero(t, l.read.reserve())
+	require.Positive(t, l.read.reserve())
+
+	// Exhausting reads does not delay writes.
+	require.Same(t, l.write, l.bucketFor(http.MethodPost))
+	require.Zero(t, l.write.reserve())
+}
+
+func TestRateLimiter_UnlimitedClass(t *testing.T) {
+	l, _ := testLimiter(RateLimit{WritesPerSecond: 1})
+
+	require.Nil(t, l.bucketFor(http.MethodGet))
+	require.NoError(t, l.wait(context.Background(), http.MethodGet))
+	require.NoError(t, (*RateLimiter)(nil).wait(context.Background(), http.MethodPost))
+}
+
+func TestRateLimiter_SlowsDownOn429AndRecovers(t *testing.T) {
+	l, clock := testLimiter(RateLimit{WritesPerSecond: 10, RecoveryInterval: time.Second})
+
+	l.observe(http.MethodPost, http.StatusTooManyRequests, http.Header{})
+	require.Equal(t, 5.0, l.write.rate)
+
+	l.observe(http.MethodPost, http.StatusTooManyRequests, http.Header{})
+	l.observe(http.MethodPost, http.StatusTooManyRequests, http.Header{})
+	l.observe(http.MethodPost, http.StatusTooManyRequests, http.Header{})
+	l.observe(http.MethodPost, http.StatusTooManyRequests, http.Header{})
+	require.Equal(t, 1.0, l.write.rate, "rate never drops below a tenth of the limit")
+
+	clock.Advance(3 * time.Second)
+	l.observe(http.MethodPost, http.StatusOK, http.Header{})
+	require.InDelta(t, 4.0, l.write.rate, 1e-9)
+
+	clock.Advance(time.Minute)
+	l.observe(http.MethodPost, http.StatusOK, http.Header{})
+	require.Equal(t, 10.0, l.write.rate)
+}
+
+func TestRateLimiter_PausesOnRetryAfter(t *testing.T) {
+	l, clock := testLimiter(RateLimit{ReadsPerSecond: 100, ReadBurst: 10})
+
+	l.observe(http.MethodGet, http.StatusTooManyRequests, http.Header{"Retry-After": {"2"}})
+	require.GreaterOrEqual(t, l.read.reserve(), 2*time.Second-time.Millisecond)
+
+	clock.Advance(3 * time.Second)
+	require.Zero(t, l.read.reserve())
+}
+
+func TestRateLimiter_PausesWhenRemainingIsExhausted(t *testing.T) {
+	l, clock := testLimiter(RateLimit{ReadsPerSecond: 100, ReadBurst: 10})
+
+	l.observe(http.MethodGet, http.StatusOK, http.Header{
+		"X-Ratelimit-Remaining": {"0"},
+		"X-Ratelimit-Reset":     {"4"},
+	})
+	require.Equal(t, 4*time.Second, l.read.reserve())
+
+	reset := clock.now.Add(10 * time.Second).Unix()
+	l.observe(http.MethodGet, http.StatusOK, http.Header{
+		"X-Ratelimit-Remaining": {"0"},
+		"X-Ratelimit-Reset":     {strconv.FormatInt(reset, 10)},
+	})
+	require.Equal(t, 10*time.Second, l.read.reserve())
+
+	clock.Advance(11 * time.Second)
+	l.observe(http.MethodGet, http.StatusOK, http.Header{"X-Ratelimit-Remaining": {"42"}})
+	require.Zero(t, l.read.reserve())
+}
+
+func TestRateLimiter_WaitRespectsDeadline(t *testing.T) {
+	l := NewRateLimiter(RateLimit{WritesPerSecond: 0.1})
+
+	require.NoError(t, l.wait(context.Background(), http.MethodPost))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := l.wait(ctx, http.MethodPost)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.InDelta(t, 0, l.write.tokens, 0.01, "the cancelled reservation is returned")
+}
+
+func TestDo_RateLimiterPacesAttempts(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusOK, body: `{}`},
+		{status: http.StatusOK, body: `{}`},
+	}}
+
+	cfg := testConfig(transport, nil)
+	cfg.RateLimiter = NewRateLimiter(RateLimit{ReadsPerSecond: 20})
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		_, err := Do[map[string]string](cfg, context.Background(), "GET", "/payouts", nil)
+		require.NoError(t, err)
+	}
+	require.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	require.Len(t, transport.requests, 2)
+}
@@ -4,17 +4,29 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
 // Config holds the configuration for making API requests.
 type Config struct {
-	BaseURL    string
-	APIKey     string
-	HTTPClient *http.Client
-	UserAgent  string
+	BaseURL        string
+	APIKey         string
+	InstanceID     string
+	HTTPClient     *http.Client
+	UserAgent      string
+	Retry          *RetryPolicy
+	Middleware     []Middleware
+	Metrics        Metrics
+	RateLimiter    *RateLimiter
+	CircuitBreaker *CircuitBreaker
+	// Credentials, when set, supplies the API key of every request instead of APIKey.
+	Credentials CredentialProvider
+	// Cache, when set, serves the reference-data operations it covers from cache.
+	Cache *Cache
 }
 
 // APIError represents an error response from the BlindPay API.
@@ -59,57 +71,283 @@ func (e *APIError) Error() string {
 	return msg
 }
 
+// RawBody is a pre-encoded request body, such as a multipart form, that is sent as is.
+type RawBody struct {
+	ContentType string
+	Data        []byte
+}
+
 // Do performs an HTTP request and decodes the response into the given type T.
-func Do[T any](cfg *Config, ctx context.Context, method, path string, body any) (T, error) {
+//
+// The call runs through cfg.Middleware, every attempt is guarded by
+// cfg.CircuitBreaker and paced by cfg.RateLimiter, and failed attempts are
+// retried according to cfg.Retry. A call rejected with 401 is sent once more
+// when cfg.Credentials provides a fresh key. CallOptions carried by ctx
+// override the configuration for this call only.
+func Do[T any](cfg *Config, ctx context.Context, method, path string, body any, opts ...Option) (T, error) {
 	var zero T
 
-	url := cfg.BaseURL + path
+	c := newCall(opts)
+	c.setIdempotencyKey(ctx, method)
 
-	var reqBody io.Reader
-	if body != nil {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
-			return zero, fmt.Errorf("failed to marshal request body: %w", err)
-		}
-		reqBody = bytes.NewReader(jsonBody)
+	req := &Request{
+		Operation:  c.operation,
+		Route:      c.route,
+		InstanceID: cfg.InstanceID,
+		Method:     method,
+		Path:       path,
+		Params:     c.params,
+		Body:       body,
+		Header:     c.header,
 	}
-
-	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
-	if err != nil {
-		return zero, fmt.Errorf("failed to create request: %w", err)
+	if req.Params == nil {
+		req.Params = body
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
 	req.Header.Set("User-Agent", cfg.UserAgent)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
+	if raw, ok := body.(*RawBody); ok {
+		req.Header.Set("Content-Type", raw.ContentType)
+	}
 
-	resp, err := cfg.HTTPClient.Do(req)
+	cfg, ctx, cancel := callOptionsFrom(ctx).apply(cfg, ctx, req)
+	defer cancel()
+
+	apiKey, err := cfg.apiKey(ctx)
 	if err != nil {
-		return zero, fmt.Errorf("request failed: %w", err)
+		return zero, c.createError(err)
 	}
-	defer resp.Body.Close()
+	req.Header.Set("Authorization", "Bearer "+apiKey)
 
-	respBody, err := io.ReadAll(resp.Body)
+	handler := chain(cfg.Middleware, func(ctx context.Context, req *Request) (*Response, error) {
+		if cfg.Cache.caches(req) {
+			return cfg.Cache.do(ctx, req, decode[T], func(ctx context.Context) (*Response, error) {
+				return roundTrip[T](cfg, ctx, req, c.stream)
+			})
+		}
+		return roundTrip[T](cfg, ctx, req, c.stream)
+	})
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	duration := time.Since(start)
+	if cfg.Metrics != nil {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		cfg.Metrics.ObserveRequest(metricsName(req), StatusClass(status), duration)
+	}
+	capture(ctx, req, resp, err, duration)
 	if err != nil {
-		return zero, fmt.Errorf("failed to read response body: %w", err)
+		return zero, c.createError(err)
+	}
+
+	if resp == nil || resp.Result == nil {
+		return zero, nil
+	}
+
+	result, ok := resp.Result.(T)
+	if !ok {
+		return zero, fmt.Errorf("unexpected result type %T for %s", resp.Result, req.Operation)
+	}
+
+	return result, nil
+}
+
+// roundTrip sends the request, retrying as allowed by cfg.Retry, and decodes
+// the final response into T. When stream is set, a successful response body
+// is handed to it instead and the response carries no Result.
+func roundTrip[T any](cfg *Config, ctx context.Context, req *Request, stream func(io.Reader) (bool, error)) (*Response, error) {
+	var payload []byte
+	switch body := req.Body.(type) {
+	case nil:
+	case *RawBody:
+		payload = body.Data
+	default:
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		payload = jsonBody
+	}
+
+	url := cfg.BaseURL + req.Path
+	group := circuitGroup(req)
+
+	maxAttempts := 1
+	if isIdempotent(req.Method, req.Header) {
+		maxAttempts = cfg.Retry.maxAttempts()
 	}
 
+	var (
+		status     int
+		respHeader http.Header
+		respBody   []byte
+		err        error
+		attempt    int
+		refreshed  bool
+	)
+
+	for attempt = 1; ; attempt++ {
+		done, waitErr := cfg.CircuitBreaker.allow(group)
+		if waitErr == nil {
+			if waitErr = cfg.RateLimiter.wait(ctx, req.Method); waitErr != nil {
+				done(false, false)
+			}
+		}
+		if waitErr != nil {
+			if attempt == 1 {
+				return &Response{}, waitErr
+			}
+			// Report the previous attempt rather than the breaker or limiter error.
+			attempt--
+			break
+		}
+
+		status, respHeader, respBody, err = send(cfg, ctx, req.Method, url, req.Header, payload, stream)
+		var streamErr *streamError
+		if errors.As(err, &streamErr) {
+			err = streamErr.err
+			if !streamErr.read {
+				// The API answered; the body could not be decoded or the
+				// caller stopped the stream.
+				done(false, true)
+				return &Response{StatusCode: status, Header: respHeader, Attempts: attempt}, err
+			}
+			if streamErr.delivered {
+				// Elements were already handed to the caller, so the
+				// attempt cannot be retried.
+				done(circuitOutcome(ctx, status, err))
+				return &Response{StatusCode: status, Header: respHeader, Attempts: attempt}, err
+			}
+		}
+		done(circuitOutcome(ctx, status, err))
+		if err == nil {
+			cfg.RateLimiter.observe(req.Method, status, respHeader)
+		}
+
+		if cfg.Metrics != nil && status == http.StatusTooManyRequests {
+			cfg.Metrics.ObserveRateLimited(metricsName(req))
+		}
+
+		// A rejected key is refreshed once, whatever the retry policy, since
+		// the API did not process the request.
+		if err == nil && status == http.StatusUnauthorized && !refreshed {
+			refreshed = true
+			if refreshCredential(cfg, ctx, req) {
+				maxAttempts++
+				continue
+			}
+		}
+
+		if attempt >= maxAttempts || !shouldRetry(cfg.Retry, ctx, status, err) {
+			break
+		}
+
+		delay, ok := cfg.Retry.delay(attempt, respHeader)
+		if !ok || !sleep(ctx, delay) {
+			break
+		}
+
+		if cfg.Metrics != nil {
+			cfg.Metrics.ObserveRetry(metricsName(req), status)
+		}
+	}
+
+	if err != nil {
+		return &Response{Attempts: attempt}, err
+	}
+
+	resp := &Response{
+		StatusCode: status,
+		Header:     respHeader,
+		Body:       respBody,
+		Attempts:   attempt,
+	}
+	if stream != nil && status >= 200 && status < 300 {
+		return resp, nil
+	}
+
+	return decode[T](resp)
+}
+
+// decode sets the Result of resp to its body decoded into T, or returns the
+// *APIError it carries.
+func decode[T any](resp *Response) (*Response, error) {
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return zero, parseAPIError(resp.StatusCode, respBody)
+		return resp, parseAPIError(resp.StatusCode, resp.Body)
 	}
 
 	// For DELETE requests that return 204 No Content, return zero value
 	if resp.StatusCode == http.StatusNoContent {
-		return zero, nil
+		var zero T
+		resp.Result = zero
+		return resp, nil
 	}
 
 	var result T
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return zero, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return resp, fmt.Errorf("failed to decode response: %w", err)
 	}
+	resp.Result = result
 
-	return result, nil
+	return resp, nil
+}
+
+// send performs a single HTTP attempt and returns the raw response. When
+// stream is set, the body of a successful response is passed to it instead of
+// being returned, and its error is returned as a *streamError.
+func send(cfg *Config, ctx context.Context, method, url string, header http.Header, payload []byte, stream func(io.Reader) (bool, error)) (int, http.Header, []byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return 0, nil, nil, &TransportError{Op: "create request", Err: err}
+	}
+	req.Header = header.Clone()
+
+	resp, err := cfg.HTTPClient.Do(req)
+	if err != nil {
+		return 0, nil, nil, &TransportError{Op: "send request", Err: err}
+	}
+	defer resp.Body.Close()
+
+	if stream != nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		body := &readErrorReader{r: resp.Body}
+		delivered, err := stream(body)
+		if body.err != nil && errors.Is(err, body.err) {
+			err = &TransportError{Op: "read response", Err: body.err}
+			return resp.StatusCode, resp.Header, nil, &streamError{err: err, read: true, delivered: delivered}
+		}
+		if err != nil {
+			return resp.StatusCode, resp.Header, nil, &streamError{err: err, delivered: delivered}
+		}
+		return resp.StatusCode, resp.Header, nil, nil
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, nil, &TransportError{Op: "read response", Err: err}
+	}
+
+	return resp.StatusCode, resp.Header, respBody, nil
+}
+
+// shouldRetry reports whether an attempt that ended with the given status or
+// transport error may be retried under the policy.
+func shouldRetry(policy *RetryPolicy, ctx context.Context, status int, err error) bool {
+	if policy == nil || ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return policy.retryableStatus(status)
 }
 
 // parseAPIError attempts to parse an API error from the response body.
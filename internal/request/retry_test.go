@@ -0,0 +1,225 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type response struct {
+	status int
+	header http.Header
+	body   string
+	// bodyErr, when set, is returned by the body once body was read.
+	bodyErr error
+}
+
+// errReader returns err from every read.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// sequenceTransport replies with the given responses in order and records every request.
+type sequenceTransport struct {
+	responses []response
+	requests  []*http.Request
+	bodies    []string
+}
+
+func (s *sequenceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+	}
+	s.requests = append(s.requests, req)
+	s.bodies = append(s.bodies, string(body))
+
+	r := s.responses[len(s.requests)-1]
+	header := r.header
+	if header == nil {
+		header = http.Header{}
+	}
+
+	var respBody io.Reader = bytes.NewReader([]byte(r.body))
+	if r.bodyErr != nil {
+		respBody = io.MultiReader(respBody, errReader{err: r.bodyErr})
+	}
+
+	return &http.Response{
+		StatusCode: r.status,
+		Header:     header,
+		Body:       io.NopCloser(respBody),
+		Request:    req,
+	}, nil
+}
+
+func testConfig(transport http.RoundTripper, policy *RetryPolicy) *Config {
+	return &Config{
+		BaseURL:    "https://api.blindpay.com",
+		APIKey:     "test_key",
+		HTTPClient: &http.Client{Transport: transport},
+		UserAgent:  "test",
+		Retry:      policy,
+	}
+}
+
+func fastPolicy() *RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.BaseDelay = time.Millisecond
+	p.MaxDelay = 10 * time.Millisecond
+	return &p
+}
+
+func TestDo_RetriesIdempotentRequests(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusServiceUnavailable, body: `{"message":"unavailable"}`},
+		{status: http.StatusBadGateway, body: `{"message":"bad gateway"}`},
+		{status: http.StatusOK, body: `{"id":"pa_000000000000"}`},
+	}}
+
+	result, err := Do[map[string]string](testConfig(transport, fastPolicy()), context.Background(), "GET", "/payouts", nil)
+	require.NoError(t, err)
+	require.Equal(t, "pa_000000000000", result["id"])
+	require.Len(t, transport.requests, 3)
+}
+
+func TestDo_StopsAfterMaxAttempts(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusTooManyRequests, body: `{"message":"slow down"}`},
+		{status: http.StatusTooManyRequests, body: `{"message":"slow down"}`},
+		{status: http.StatusTooManyRequests, body: `{"message":"slow down"}`},
+	}}
+
+	_, err := Do[map[string]string](testConfig(transport, fastPolicy()), context.Background(), "DELETE", "/payouts", nil)
+	require.Error(t, err)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	require.Len(t, transport.requests, 3)
+}
+
+func TestDo_DoesNotRetryNonIdempotentRequests(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusServiceUnavailable, body: `{"message":"unavailable"}`},
+	}}
+
+	_, err := Do[map[string]string](testConfig(transport, fastPolicy()), context.Background(), "POST", "/payouts", map[string]string{"a": "b"})
+	require.Error(t, err)
+	require.Len(t, transport.requests, 1)
+}
+
+func TestDo_DoesNotRetryOtherStatuses(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusBadRequest, body: `{"message":"bad request"}`},
+	}}
+
+	_, err := Do[map[string]string](testConfig(transport, fastPolicy()), context.Background(), "GET", "/payouts", nil)
+	require.Error(t, err)
+	require.Len(t, transport.requests, 1)
+}
+
+func TestDo_RespectsRetryAfter(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"1"}}},
+		{status: http.StatusOK, body: `{}`},
+	}}
+
+	policy := fastPolicy()
+	policy.MaxDelay = 2 * time.Second
+
+	start := time.Now()
+	_, err := Do[map[string]string](testConfig(transport, policy), context.Background(), "GET", "/payouts", nil)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+	require.Len(t, transport.requests, 2)
+}
+
+func TestDo_StopsWhenRetryAfterExceedsMaxDelay(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"120"}}},
+	}}
+
+	_, err := Do[map[string]string](testConfig(transport, fastPolicy()), context.Background(), "GET", "/payouts", nil)
+	require.Error(t, err)
+	require.Len(t, transport.requests, 1)
+}
+
+func TestDo_StopsWhenDeadlineCannotBeMet(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusServiceUnavailable},
+	}}
+
+	policy := fastPolicy()
+	policy.BaseDelay = time.Second
+	policy.MaxDelay = time.Second
+	policy.Jitter = 0
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := Do[map[string]string](testConfig(transport, policy), ctx, "GET", "/payouts", nil)
+	require.Error(t, err)
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+	require.Len(t, transport.requests, 1)
+}
+
+func TestDo_ResendsBodyOnRetry(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusServiceUnavailable},
+		{status: http.StatusOK, body: `{}`},
+	}}
+
+	cfg := testConfig(transport, fastPolicy())
+	_, err := Do[map[string]string](cfg, context.Background(), "DELETE", "/payouts", map[string]string{"a": "b"})
+	require.NoError(t, err)
+	require.Len(t, transport.bodies, 2)
+	require.Equal(t, transport.bodies[0], transport.bodies[1])
+	require.JSONEq(t, `{"a":"b"}`, transport.bodies[1])
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	d, ok := parseRetryAfter("3")
+	require.True(t, ok)
+	require.Equal(t, 3*time.Second, d)
+
+	d, ok = parseRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	require.True(t, ok)
+	require.Equal(t, time.Duration(0), d)
+
+	_, ok = parseRetryAfter("soon")
+	require.False(t, ok)
+
+	_, ok = parseRetryAfter("")
+	require.False(t, ok)
+}
+
+func TestDo_RetriesRequestsWithIdempotencyKey(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusGatewayTimeout},
+		{status: http.StatusOK, body: `{}`},
+	}}
+
+	cfg := testConfig(transport, fastPolicy())
+	_, err := Do[map[string]string](cfg, context.Background(), "POST", "/payouts", map[string]string{"a": "b"}, WithIdempotency("key-1", nil))
+	require.NoError(t, err)
+	require.Len(t, transport.requests, 2)
+	require.Equal(t, "key-1", transport.requests[0].Header.Get("Idempotency-Key"))
+	require.Equal(t, "key-1", transport.requests[1].Header.Get("Idempotency-Key"))
+}
+
+func TestNewIdempotencyKey(t *testing.T) {
+	key := NewIdempotencyKey()
+	require.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, key)
+	require.NotEqual(t, key, NewIdempotencyKey())
+}
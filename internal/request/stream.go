@@ -0,0 +1,101 @@
+package request
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// streamError carries an error returned while consuming a streamed response
+// body. Only failures to read the body before the first element was handed to
+// the caller may be retried.
+type streamError struct {
+	err error
+	// read is set when err comes from reading the body.
+	read bool
+	// delivered is set once an element was handed to the caller.
+	delivered bool
+}
+
+// Error implements the error interface for streamError.
+func (e *streamError) Error() string {
+	return e.err.Error()
+}
+
+// withStream hands the body of a successful response to fn instead of
+// buffering and decoding it. fn reports whether it handed any element to the
+// caller.
+func withStream(fn func(io.Reader) (bool, error)) Option {
+	return func(c *call) {
+		c.stream = fn
+	}
+}
+
+// Stream performs an HTTP request whose response is a JSON array and calls fn
+// with each element as it is decoded, without buffering the whole body.
+//
+// The call runs through the same middleware, rate limiter, circuit breaker and
+// retries as Do. A failure to read the body is retried as the retry policy
+// allows until fn was called with the first element, and never after. Decode
+// errors are not retried. An error returned by fn stops the stream and is
+// returned as is.
+func Stream[T any](cfg *Config, ctx context.Context, method, path string, fn func(T) error, opts ...Option) error {
+	opts = append(opts[:len(opts):len(opts)], withStream(func(r io.Reader) (bool, error) {
+		return decodeArray(r, fn)
+	}))
+
+	_, err := Do[struct{}](cfg, ctx, method, path, nil, opts...)
+	return err
+}
+
+// decodeArray decodes the JSON array read from r one element at a time and
+// calls fn with each of them. A null array holds no elements. It reports
+// whether fn was called.
+func decodeArray[T any](r io.Reader, fn func(T) error) (bool, error) {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return false, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if tok == nil {
+		return false, nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return false, fmt.Errorf("failed to decode response: expected a JSON array, got %v", tok)
+	}
+
+	delivered := false
+	for dec.More() {
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			return delivered, fmt.Errorf("failed to decode response: %w", err)
+		}
+		delivered = true
+		if err := fn(item); err != nil {
+			return delivered, err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return delivered, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return delivered, nil
+}
+
+// readErrorReader records the error of the first failed read of r, other
+// than io.EOF.
+type readErrorReader struct {
+	r   io.Reader
+	err error
+}
+
+// Read implements io.Reader for readErrorReader.
+func (r *readErrorReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && err != io.EOF && r.err == nil {
+		r.err = err
+	}
+	return n, err
+}
@@ -0,0 +1,104 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream_DecodesElements(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusServiceUnavailable, body: `{"message":"unavailable"}`},
+		{status: http.StatusOK, body: `[{"id":"pa_000000000000"},{"id":"pa_111111111111"}]`},
+	}}
+
+	cfg := testConfig(transport, fastPolicy())
+
+	var ids []string
+	err := Stream(cfg, context.Background(), "GET", "/instances/in_000000000000/export/payouts", func(item map[string]string) error {
+		ids = append(ids, item["id"])
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"pa_000000000000", "pa_111111111111"}, ids)
+	require.Len(t, transport.requests, 2)
+}
+
+func TestStream_DoesNotRetryDecodeErrors(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusOK, body: `[{"id":"pa_000000000000"},{"id":`},
+		{status: http.StatusOK, body: `[]`},
+	}}
+
+	cfg := testConfig(transport, fastPolicy())
+
+	var ids []string
+	err := Stream(cfg, context.Background(), "GET", "/instances/in_000000000000/export/payouts", func(item map[string]string) error {
+		ids = append(ids, item["id"])
+		return nil
+	})
+	require.ErrorContains(t, err, "failed to decode response")
+	require.Equal(t, []string{"pa_000000000000"}, ids)
+	require.Len(t, transport.requests, 1)
+}
+
+func TestStream_RetriesReadErrorsBeforeFirstElement(t *testing.T) {
+	reset := errors.New("connection reset")
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusOK, body: `[`, bodyErr: reset},
+		{status: http.StatusOK, body: `[{"id":"pa_000000000000"}]`},
+	}}
+
+	cfg := testConfig(transport, fastPolicy())
+
+	var ids []string
+	err := Stream(cfg, context.Background(), "GET", "/instances/in_000000000000/export/payouts", func(item map[string]string) error {
+		ids = append(ids, item["id"])
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"pa_000000000000"}, ids)
+	require.Len(t, transport.requests, 2)
+}
+
+func TestStream_ReadErrorsAfterFirstElement(t *testing.T) {
+	reset := errors.New("connection reset")
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusOK, body: `[{"id":"pa_000000000000"},`, bodyErr: reset},
+		{status: http.StatusOK, body: `[]`},
+	}}
+
+	cfg := testConfig(transport, fastPolicy())
+	cfg.CircuitBreaker = NewCircuitBreaker(CircuitBreakerPolicy{FailureThreshold: 1})
+
+	var ids []string
+	err := Stream(cfg, context.Background(), "GET", "/instances/in_000000000000/export/payouts", func(item map[string]string) error {
+		ids = append(ids, item["id"])
+		return nil
+	}, WithOperation("payouts.ExportEach", "/instances/{instance_id}/export/payouts"))
+	require.ErrorIs(t, err, reset)
+	var transportErr *TransportError
+	require.ErrorAs(t, err, &transportErr)
+	require.Equal(t, []string{"pa_000000000000"}, ids)
+	require.Len(t, transport.requests, 1, "elements were handed to the caller")
+
+	_, err = cfg.CircuitBreaker.allow(GroupPayouts)
+	require.ErrorIs(t, err, ErrCircuitOpen, "the read error counts as a failure")
+}
+
+func TestStream_NullArray(t *testing.T) {
+	transport := &sequenceTransport{responses: []response{
+		{status: http.StatusOK, body: `null`},
+	}}
+
+	cfg := testConfig(transport, nil)
+
+	err := Stream(cfg, context.Background(), "GET", "/instances/in_000000000000/export/payins", func(item map[string]string) error {
+		t.Fatal("unexpected element")
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -27,6 +27,28 @@ const (
 	NetworkSolanaDevnet    Network = "solana_devnet"
 )
 
+// IsTestnet reports whether the network is a test network, such as
+// base_sepolia or solana_devnet.
+func (n Network) IsTestnet() bool {
+	switch n {
+	case NetworkSepolia, NetworkArbitrumSepolia, NetworkBaseSepolia, NetworkPolygonAmoy,
+		NetworkStellarTestnet, NetworkSolanaDevnet:
+		return true
+	}
+	return false
+}
+
+// IsMainnet reports whether the network is a production network, such as
+// base or solana.
+func (n Network) IsMainnet() bool {
+	switch n {
+	case NetworkBase, NetworkArbitrum, NetworkPolygon, NetworkEthereum, NetworkStellar,
+		NetworkTron, NetworkSolana:
+		return true
+	}
+	return false
+}
+
 type StablecoinToken string
 
 const (
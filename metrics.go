@@ -0,0 +1,51 @@
+package blindpay
+
+import (
+	"github.com/blindpaylabs/blindpay-go/internal/request"
+)
+
+// Webhook verification outcomes reported to Metrics.
+const (
+	WebhookVerificationValid   = "valid"
+	WebhookVerificationInvalid = "invalid"
+)
+
+// Metrics receives measurements from the client: request counts and latency
+// per operation and status class, retries, rate-limited responses and
+// webhook verification outcomes.
+//
+// Implementations must be safe for concurrent use. See the promblindpay
+// package for a Prometheus implementation.
+type Metrics interface {
+	request.Metrics
+
+	// ObserveWebhookVerification records the outcome of a webhook signature
+	// verification made with Client.VerifyWebhookSignature or
+	// Client.ParseWebhookEvent.
+	ObserveWebhookVerification(outcome string)
+}
+
+// VerifyWebhookSignature verifies the BlindPay webhook signature like the
+// package-level VerifyWebhookSignature, and reports the outcome to the
+// client metrics.
+func (c *Client) VerifyWebhookSignature(secret, id, timestamp, payload, signature string) bool {
+	err := VerifyWebhook(webhookSignatureHeader(id, timestamp, signature), []byte(payload), secret)
+	c.observeWebhookVerification(err)
+	return err == nil
+}
+
+// observeWebhookVerification reports a webhook verification that returned
+// err to the client metrics. Only a verification that returned no error is
+// valid; a bad secret, a missing header, a stale timestamp and a mismatched
+// signature are all invalid.
+func (c *Client) observeWebhookVerification(err error) {
+	if c.metrics == nil {
+		return
+	}
+
+	outcome := WebhookVerificationInvalid
+	if err == nil {
+		outcome = WebhookVerificationValid
+	}
+	c.metrics.ObserveWebhookVerification(outcome)
+}
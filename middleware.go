@@ -0,0 +1,33 @@
+package blindpay
+
+import (
+	"github.com/blindpaylabs/blindpay-go/internal/request"
+)
+
+// Request describes a logical API call as seen by middleware.
+// It is an alias to the internal request.Request for convenience.
+type Request = request.Request
+
+// Response describes the outcome of a logical API call as seen by middleware.
+// It is an alias to the internal request.Response for convenience.
+type Response = request.Response
+
+// Handler performs a logical API call.
+// It is an alias to the internal request.Handler for convenience.
+type Handler = request.Handler
+
+// Middleware wraps every API call made by the client, including file uploads.
+// It is an alias to the internal request.Middleware for convenience.
+//
+// A middleware sees the operation name (for example "payouts.CreateEvm"), the
+// instance ID and the typed params of the call, and receives the decoded
+// result or the *APIError returned by the API:
+//
+//	audit := func(next blindpay.Handler) blindpay.Handler {
+//		return func(ctx context.Context, req *blindpay.Request) (*blindpay.Response, error) {
+//			resp, err := next(ctx, req)
+//			log.Printf("%s on %s: %v", req.Operation, req.InstanceID, err)
+//			return resp, err
+//		}
+//	}
+type Middleware = request.Middleware
@@ -1,6 +1,9 @@
 package blindpay
 
-import "net/http"
+import (
+	"log/slog"
+	"net/http"
+)
 
 type Option func(*Client)
 
@@ -19,3 +22,90 @@ func WithBaseURL(baseURL string) Option {
 		}
 	}
 }
+
+// WithRetryPolicy sets the policy used to retry failed requests, such as
+// DefaultRetryPolicy. Requests are not retried unless a policy is given.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = &policy
+	}
+}
+
+// WithMiddleware adds middleware that runs around every API call.
+// Middleware runs in the order given; the first one is the outermost.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// WithLogger makes the client write one structured record per API call to logger.
+//
+// Records carry the operation, method, path template, status, latency, attempt
+// count and, for failed calls, the BlindPay trace ID. Request and response
+// bodies are only logged when the logger is enabled for debug level. The
+// Authorization header and sensitive KYC and bank account fields such as
+// tax_id or account_number are always redacted.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithMetrics makes the client report request, retry, rate-limit and webhook
+// verification measurements to metrics.
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// WithRateLimit paces API calls on the client side with separate token
+// buckets for reads and writes. The limiter is shared by every sub-client and
+// slows down automatically when the API answers with 429 or reports an
+// exhausted X-RateLimit-Remaining, then recovers gradually.
+func WithRateLimit(limit RateLimit) Option {
+	return func(c *Client) {
+		c.rateLimit = &limit
+	}
+}
+
+// WithCircuitBreaker turns on a circuit breaker per endpoint group (quotes,
+// payouts, payins, receivers, uploads and so on). After policy.FailureThreshold
+// consecutive 5xx responses or transport errors in a group, calls to it fail
+// fast with an error matching ErrCircuitOpen until a half-open probe succeeds.
+// Zero fields of policy take their value from DefaultCircuitBreakerPolicy.
+func WithCircuitBreaker(policy CircuitBreakerPolicy) Option {
+	return func(c *Client) {
+		c.breaker = &policy
+	}
+}
+
+// WithEnvironment makes the client reject, before sending them, calls whose
+// body carries a network that does not belong to env: mainnet networks such as
+// NetworkBase in sandbox, and testnet networks such as NetworkBaseSepolia or
+// NetworkSolanaDevnet in production. Rejected calls fail with an error
+// matching ErrInvalidParams.
+func WithEnvironment(env Environment) Option {
+	return func(c *Client) {
+		c.environment = env
+	}
+}
+
+// WithCredentials makes the client ask provider for the API key of every
+// request instead of using the key given to New, which may then be empty.
+func WithCredentials(provider CredentialProvider) Option {
+	return func(c *Client) {
+		c.credentials = provider
+	}
+}
+
+// WithCache serves the reference-data operations listed in policy.TTL, such as
+// available.GetRails or fees.Get, from a cache. Concurrent misses for the same
+// request share a single API call. Use Client.InvalidateCache to drop cached
+// responses before they expire.
+func WithCache(policy CachePolicy) Option {
+	return func(c *Client) {
+		c.cache = &policy
+	}
+}
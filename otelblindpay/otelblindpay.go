@@ -0,0 +1,134 @@
+// Package otelblindpay provides OpenTelemetry tracing for the BlindPay client.
+//
+// Every SDK operation is recorded as a client span carrying the operation
+// name, instance ID, resource ID, HTTP status, retry count and the BlindPay
+// trace ID of failed calls. The span context is propagated to BlindPay in the
+// outgoing request headers.
+//
+//	client, err := blindpay.New(apiKey, instanceID,
+//		otelblindpay.WithTracerProvider(tp),
+//	)
+//
+// The package is a module of its own, so that only the programs importing it
+// depend on OpenTelemetry:
+//
+//	go get github.com/blindpaylabs/blindpay-go/otelblindpay
+package otelblindpay
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/blindpaylabs/blindpay-go"
+)
+
+// instrumentationName identifies this package as the source of the spans.
+const instrumentationName = "github.com/blindpaylabs/blindpay-go/otelblindpay"
+
+// Span attribute keys specific to BlindPay.
+const (
+	OperationKey  = attribute.Key("blindpay.operation")
+	InstanceIDKey = attribute.Key("blindpay.instance_id")
+	ResourceIDKey = attribute.Key("blindpay.resource_id")
+	RetryCountKey = attribute.Key("blindpay.retry_count")
+	TraceIDKey    = attribute.Key("blindpay.trace_id")
+)
+
+type config struct {
+	propagators propagation.TextMapPropagator
+}
+
+// Option configures the tracing middleware.
+type Option func(*config)
+
+// WithPropagators sets the propagators used to inject the span context into
+// outgoing requests. The global propagators are used by default.
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		if propagators != nil {
+			c.propagators = propagators
+		}
+	}
+}
+
+// WithTracerProvider returns a client option that records one span per SDK
+// operation with the given tracer provider. The global tracer provider is
+// used when tp is nil.
+func WithTracerProvider(tp trace.TracerProvider, opts ...Option) blindpay.Option {
+	return blindpay.WithMiddleware(Middleware(tp, opts...))
+}
+
+// Middleware returns a client middleware that records one span per SDK
+// operation with the given tracer provider.
+func Middleware(tp trace.TracerProvider, opts ...Option) blindpay.Middleware {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+
+	cfg := &config{
+		propagators: otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	tracer := tp.Tracer(instrumentationName, trace.WithInstrumentationVersion(blindpay.Version))
+
+	return func(next blindpay.Handler) blindpay.Handler {
+		return func(ctx context.Context, req *blindpay.Request) (*blindpay.Response, error) {
+			name := req.Operation
+			if name == "" {
+				name = req.Method
+			}
+
+			attrs := []attribute.KeyValue{
+				OperationKey.String(req.Operation),
+				InstanceIDKey.String(req.InstanceID),
+				semconv.HTTPRequestMethodKey.String(req.Method),
+			}
+			if req.Route != "" {
+				attrs = append(attrs, semconv.URLTemplate(req.Route))
+			}
+			if id := req.ResourceID(); id != "" {
+				attrs = append(attrs, ResourceIDKey.String(id))
+			}
+
+			ctx, span := tracer.Start(ctx, name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+			)
+			defer span.End()
+
+			cfg.propagators.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			resp, err := next(ctx, req)
+
+			if resp != nil {
+				if resp.StatusCode != 0 {
+					span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+				}
+				if resp.Attempts > 0 {
+					span.SetAttributes(RetryCountKey.Int(resp.Attempts - 1))
+				}
+			}
+
+			if err != nil {
+				var apiErr *blindpay.APIError
+				if errors.As(err, &apiErr) && apiErr.TraceID != "" {
+					span.SetAttributes(TraceIDKey.String(apiErr.TraceID))
+				}
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+
+			return resp, err
+		}
+	}
+}
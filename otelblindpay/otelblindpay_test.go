@@ -0,0 +1,93 @@
+package otelblindpay
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/blindpaylabs/blindpay-go"
+	"github.com/blindpaylabs/blindpay-go/internal/blindpaytest"
+	"github.com/blindpaylabs/blindpay-go/payouts"
+)
+
+func newClient(t *testing.T, rt http.RoundTripper, exporter *tracetest.InMemoryExporter) *blindpay.Client {
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	client, err := blindpay.New("test_key", "in_000000000000",
+		blindpay.WithHTTPClient(&http.Client{Transport: rt}),
+		WithTracerProvider(tp, WithPropagators(propagation.TraceContext{})),
+	)
+	require.NoError(t, err)
+	return client
+}
+
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestMiddleware_RecordsSpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	rt := &blindpaytest.RoundTripper{
+		T:      t,
+		Out:    json.RawMessage(`{"id":"pa_000000000000","status":"processing"}`),
+		Method: http.MethodGet,
+		Path:   "/v1/instances/in_000000000000/payouts/pa_000000000000",
+	}
+
+	client := newClient(t, rt, exporter)
+	_, err := client.Payouts.Get(context.Background(), "pa_000000000000")
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+
+	span := spans[0]
+	require.Equal(t, "payouts.Get", span.Name)
+	require.Equal(t, trace.SpanKindClient, span.SpanKind)
+
+	attrs := attributes(span)
+	require.Equal(t, "payouts.Get", attrs[OperationKey].AsString())
+	require.Equal(t, "in_000000000000", attrs[InstanceIDKey].AsString())
+	require.Equal(t, "pa_000000000000", attrs[ResourceIDKey].AsString())
+	require.Equal(t, int64(http.StatusOK), attrs["http.response.status_code"].AsInt64())
+	require.Equal(t, int64(0), attrs[RetryCountKey].AsInt64())
+
+	traceparent := rt.Requests[0].Header.Get("Traceparent")
+	require.Contains(t, traceparent, span.SpanContext.TraceID().String())
+}
+
+func TestMiddleware_RecordsAPIError(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	rt := &blindpaytest.RoundTripper{
+		T:      t,
+		Status: http.StatusBadRequest,
+		Out:    json.RawMessage(`{"message":"quote expired","trace_id":"tr_000000000000"}`),
+	}
+
+	client := newClient(t, rt, exporter)
+	_, err := client.Payouts.CreateEvm(context.Background(), &payouts.CreateEvmParams{QuoteID: "qu_000000000000", SenderWalletAddress: "0x123...890"})
+	require.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+
+	span := spans[0]
+	require.Equal(t, codes.Error, span.Status.Code)
+
+	attrs := attributes(span)
+	require.Equal(t, "tr_000000000000", attrs[TraceIDKey].AsString())
+	require.Equal(t, int64(http.StatusBadRequest), attrs["http.response.status_code"].AsInt64())
+}
@@ -0,0 +1,29 @@
+//go:build go1.23
+
+package pagination
+
+import "iter"
+
+// All returns the remaining items of it as an iter.Seq2 for use with range:
+//
+//	for payout, err := range client.Payouts.ListAll(ctx, nil).All() {
+//		if err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+//
+// An error is yielded once, as the last pair, with the zero value of T.
+func (it *Iterator[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for it.Next() {
+			if !yield(it.Value(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+//go:build go1.23
+
+package pagination
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterator_All(t *testing.T) {
+	var calls int
+	var seen []int
+	for v, err := range New(context.Background(), pages(&calls, []int{1, 2}, []int{3})).All() {
+		require.NoError(t, err)
+		seen = append(seen, v)
+	}
+	require.Equal(t, []int{1, 2, 3}, seen)
+
+	boom := errors.New("boom")
+	it := New(context.Background(), func(ctx context.Context) ([]int, bool, error) {
+		return nil, false, boom
+	})
+	for _, err := range it.All() {
+		require.Same(t, boom, err)
+	}
+}
@@ -0,0 +1,111 @@
+// Package pagination walks the pages of BlindPay list endpoints.
+//
+// List methods such as payouts.Client.ListAll return an Iterator that fetches
+// pages lazily, as the caller consumes items:
+//
+//	it := client.Payouts.ListAll(ctx, &payouts.ListParams{Limit: 100})
+//	for it.Next() {
+//		payout := it.Value()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+package pagination
+
+import (
+	"context"
+)
+
+// PageFunc fetches the next page of a list. It returns the items of the page
+// and whether more pages follow. Implementations keep track of their position,
+// so every call returns the page after the previous one.
+type PageFunc[T any] func(ctx context.Context) (items []T, hasMore bool, err error)
+
+// Iterator walks the items of a paginated list, fetching each page when the
+// previous one is exhausted. It stops after a page that reports no more
+// pages, after an empty page, or on the first error. An Iterator is not safe
+// for concurrent use.
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch PageFunc[T]
+
+	page  []T
+	index int
+	value T
+	more  bool
+	err   error
+}
+
+// New returns an iterator over the pages returned by fetch. Every page is
+// fetched with ctx, and iteration stops with ctx.Err() once ctx is done.
+func New[T any](ctx context.Context, fetch PageFunc[T]) *Iterator[T] {
+	return &Iterator[T]{
+		ctx:   ctx,
+		fetch: fetch,
+		more:  true,
+	}
+}
+
+// Next advances the iterator to the next item, fetching a new page if needed.
+// It returns false when there are no more items or an error occurred; use Err
+// to tell the two apart.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.index >= len(it.page) {
+		if !it.more {
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		page, more, err := it.fetch(it.ctx)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page = page
+		it.index = 0
+		it.more = more && len(page) > 0
+	}
+
+	it.value = it.page[it.index]
+	it.index++
+	return true
+}
+
+// Value returns the current item. It is only valid after Next returned true.
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Collect returns all the remaining items of it.
+func Collect[T any](it *Iterator[T]) ([]T, error) {
+	var items []T
+	for it.Next() {
+		items = append(items, it.Value())
+	}
+	return items, it.Err()
+}
+
+// ForEach calls fn with each remaining item of it. An error returned by fn
+// stops the iteration and is returned as is.
+func ForEach[T any](it *Iterator[T], fn func(T) error) error {
+	for it.Next() {
+		if err := fn(it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
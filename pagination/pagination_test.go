@@ -0,0 +1,94 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pages returns a PageFunc serving the given pages in order, the last one
+// reporting no more pages, and counts the calls made to it.
+func pages(calls *int, items ...[]int) PageFunc[int] {
+	return func(ctx context.Context) ([]int, bool, error) {
+		page := items[*calls]
+		*calls++
+		return page, *calls < len(items), nil
+	}
+}
+
+func TestIterator_WalksPagesLazily(t *testing.T) {
+	var calls int
+	it := New(context.Background(), pages(&calls, []int{1, 2}, []int{3}, []int{4, 5}))
+
+	require.True(t, it.Next())
+	require.Equal(t, 1, it.Value())
+	require.Equal(t, 1, calls)
+
+	rest, err := Collect(it)
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 3, 4, 5}, rest)
+	require.Equal(t, 3, calls)
+	require.False(t, it.Next())
+}
+
+func TestIterator_StopsOnEmptyPage(t *testing.T) {
+	var calls int
+	it := New(context.Background(), func(ctx context.Context) ([]int, bool, error) {
+		calls++
+		return nil, true, nil
+	})
+
+	items, err := Collect(it)
+	require.NoError(t, err)
+	require.Empty(t, items)
+	require.Equal(t, 1, calls)
+}
+
+func TestIterator_SurfacesErrors(t *testing.T) {
+	boom := errors.New("boom")
+
+	var calls int
+	it := New(context.Background(), func(ctx context.Context) ([]int, bool, error) {
+		calls++
+		if calls > 1 {
+			return nil, false, boom
+		}
+		return []int{1}, true, nil
+	})
+
+	items, err := Collect(it)
+	require.Same(t, boom, err)
+	require.Equal(t, []int{1}, items)
+}
+
+func TestIterator_RespectsContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls int
+	it := New(ctx, pages(&calls, []int{1}, []int{2}))
+
+	require.True(t, it.Next())
+	cancel()
+	require.False(t, it.Next())
+	require.ErrorIs(t, it.Err(), context.Canceled)
+	require.Equal(t, 1, calls)
+}
+
+func TestForEach(t *testing.T) {
+	stop := errors.New("stop")
+
+	var calls int
+	var seen []int
+	err := ForEach(New(context.Background(), pages(&calls, []int{1, 2}, []int{3})), func(v int) error {
+		seen = append(seen, v)
+		if v == 2 {
+			return stop
+		}
+		return nil
+	})
+	require.Same(t, stop, err)
+	require.Equal(t, []int{1, 2}, seen)
+	require.Equal(t, 1, calls)
+}
@@ -50,36 +50,40 @@ func NewClient(cfg *config.Config) *Client {
 // List retrieves all partner fees for the instance.
 func (c *Client) List(ctx context.Context) ([]PartnerFee, error) {
 	path := fmt.Sprintf("/instances/%s/partner-fees", c.instanceID)
-	return request.Do[[]PartnerFee](c.cfg, ctx, "GET", path, nil)
+	return request.Do[[]PartnerFee](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("partnerfees.List", "/instances/{instance_id}/partner-fees"))
 }
 
 // Create creates a new partner fee configuration.
 func (c *Client) Create(ctx context.Context, params *CreatePartnerFeeParams) (*PartnerFee, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/partner-fees", c.instanceID)
-	return request.Do[*PartnerFee](c.cfg, ctx, "POST", path, params)
+	return request.Do[*PartnerFee](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("partnerfees.Create", "/instances/{instance_id}/partner-fees"))
 }
 
 // Get retrieves a specific partner fee by ID.
 func (c *Client) Get(ctx context.Context, id string) (*PartnerFee, error) {
 	if id == "" {
-		return nil, fmt.Errorf("id cannot be empty")
+		return nil, request.EmptyParam("id")
 	}
 
 	path := fmt.Sprintf("/instances/%s/partner-fees/%s", c.instanceID, id)
-	return request.Do[*PartnerFee](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*PartnerFee](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("partnerfees.Get", "/instances/{instance_id}/partner-fees/{id}"))
 }
 
 // Delete deletes a partner fee configuration.
 func (c *Client) Delete(ctx context.Context, id string) error {
 	if id == "" {
-		return fmt.Errorf("id cannot be empty")
+		return request.EmptyParam("id")
 	}
 
 	path := fmt.Sprintf("/instances/%s/partner-fees/%s", c.instanceID, id)
-	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil)
+	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil,
+		request.WithOperation("partnerfees.Delete", "/instances/{instance_id}/partner-fees/{id}"))
 	return err
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/blindpaylabs/blindpay-go/internal/config"
 	"github.com/blindpaylabs/blindpay-go/internal/request"
 	"github.com/blindpaylabs/blindpay-go/internal/types"
+	"github.com/blindpaylabs/blindpay-go/pagination"
 )
 
 // Payin represents a payin transaction.
@@ -156,31 +157,72 @@ func (c *Client) List(ctx context.Context, params *ListParams) (*ListResponse, e
 		}
 	}
 
-	return request.Do[*ListResponse](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*ListResponse](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("payins.List", "/instances/{instance_id}/payins"),
+		request.WithParams(params))
+}
+
+// ListAll returns an iterator over all the payins matching params. It
+// fetches pages lazily, starting at params.Offset, and params.Limit serves as
+// the page size hint.
+func (c *Client) ListAll(ctx context.Context, params *ListParams) *pagination.Iterator[Payin] {
+	page := ListParams{}
+	if params != nil {
+		page = *params
+	}
+
+	return pagination.New(ctx, func(ctx context.Context) ([]Payin, bool, error) {
+		resp, err := c.List(ctx, &page)
+		if err != nil {
+			return nil, false, err
+		}
+		page.Offset += len(resp.Data)
+		return resp.Data, resp.Pagination.HasMore, nil
+	})
 }
 
 // Get retrieves a specific payin by ID.
 func (c *Client) Get(ctx context.Context, payinID string) (*Payin, error) {
 	if payinID == "" {
-		return nil, fmt.Errorf("payin ID cannot be empty")
+		return nil, request.EmptyParam("payin ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/payins/%s", c.instanceID, payinID)
-	return request.Do[*Payin](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*Payin](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("payins.Get", "/instances/{instance_id}/payins/{payin_id}"))
 }
 
 // GetTrack retrieves tracking information for a payin.
 func (c *Client) GetTrack(ctx context.Context, payinID string) (*Payin, error) {
 	if payinID == "" {
-		return nil, fmt.Errorf("payin ID cannot be empty")
+		return nil, request.EmptyParam("payin ID")
 	}
 
 	path := fmt.Sprintf("/e/payins/%s", payinID)
-	return request.Do[*Payin](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*Payin](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("payins.GetTrack", "/e/payins/{payin_id}"))
 }
 
 // Export exports payins with filters.
 func (c *Client) Export(ctx context.Context, status types.TransactionStatus, limit, offset int) ([]Payin, error) {
+	return request.Do[[]Payin](c.cfg, ctx, "GET", c.exportPath(status, limit, offset), nil,
+		request.WithOperation("payins.Export", "/instances/{instance_id}/export/payins"))
+}
+
+// ExportEach streams the payins of an export, calling fn with each payin as
+// it is decoded instead of buffering the whole response. An error returned by
+// fn stops the export and is returned as is.
+func (c *Client) ExportEach(ctx context.Context, status types.TransactionStatus, limit, offset int, fn func(Payin) error) error {
+	if fn == nil {
+		return request.NilParam("fn")
+	}
+
+	return request.Stream(c.cfg, ctx, "GET", c.exportPath(status, limit, offset), fn,
+		request.WithOperation("payins.ExportEach", "/instances/{instance_id}/export/payins"))
+}
+
+// exportPath returns the path of an export with the given filters.
+func (c *Client) exportPath(status types.TransactionStatus, limit, offset int) string {
 	path := fmt.Sprintf("/instances/%s/export/payins", c.instanceID)
 
 	q := url.Values{}
@@ -197,13 +239,13 @@ func (c *Client) Export(ctx context.Context, status types.TransactionStatus, lim
 		path += "?" + q.Encode()
 	}
 
-	return request.Do[[]Payin](c.cfg, ctx, "GET", path, nil)
+	return path
 }
 
 // CreateEvm creates an EVM payin.
 func (c *Client) CreateEvm(ctx context.Context, payinQuoteID string) (*CreateEvmResponse, error) {
 	if payinQuoteID == "" {
-		return nil, fmt.Errorf("payin quote ID cannot be empty")
+		return nil, request.EmptyParam("payin quote ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/payins/evm", c.instanceID)
@@ -211,5 +253,6 @@ func (c *Client) CreateEvm(ctx context.Context, payinQuoteID string) (*CreateEvm
 		"payin_quote_id": payinQuoteID,
 	}
 
-	return request.Do[*CreateEvmResponse](c.cfg, ctx, "POST", path, body)
+	return request.Do[*CreateEvmResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("payins.CreateEvm", "/instances/{instance_id}/payins/evm"))
 }
@@ -290,3 +290,37 @@ func TestPayins_CreateQuote(t *testing.T) {
 	require.Equal(t, quoteID, quote.ID)
 	require.Equal(t, 5240.0, quote.SenderAmount)
 }
+
+func TestPayins_ExportEach(t *testing.T) {
+	instanceID := "in_000000000000"
+
+	cfg := &config.Config{
+		BaseURL:    "https://api.blindpay.com",
+		APIKey:     "test_key",
+		InstanceID: instanceID,
+		HTTPClient: &http.Client{
+			Transport: &blindpaytest.RoundTripper{
+				T: t,
+				Out: json.RawMessage(`[
+					{"id":"re_000000000000","status":"completed","payin_quote_id":"pq_000000000000"},
+					{"id":"re_111111111111","status":"completed","payin_quote_id":"pq_111111111111"}
+				]`),
+				Method: http.MethodGet,
+				Path:   fmt.Sprintf("/instances/%s/export/payins", instanceID),
+			},
+		},
+		UserAgent: "test",
+	}
+
+	client := NewClient(cfg)
+
+	var payins []Payin
+	err := client.ExportEach(context.Background(), types.TransactionStatusCompleted, 100, 0, func(payin Payin) error {
+		payins = append(payins, payin)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, payins, 2)
+	require.Equal(t, "re_111111111111", payins[1].ID)
+	require.Equal(t, types.TransactionStatusCompleted, payins[1].Status)
+}
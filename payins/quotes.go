@@ -65,19 +65,21 @@ type QuotesClient struct {
 // Create creates a new payin quote.
 func (c *QuotesClient) Create(ctx context.Context, params *CreateQuoteParams) (*CreateQuoteResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/payin-quotes", c.instanceID)
-	return request.Do[*CreateQuoteResponse](c.cfg, ctx, "POST", path, params)
+	return request.Do[*CreateQuoteResponse](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("payins.quotes.Create", "/instances/{instance_id}/payin-quotes"))
 }
 
 // GetFxRate retrieves the current FX rate for a payin currency pair.
 func (c *QuotesClient) GetFxRate(ctx context.Context, params *GetFxRateParams) (*GetFxRateResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/payin-quotes/fx", c.instanceID)
-	return request.Do[*GetFxRateResponse](c.cfg, ctx, "POST", path, params)
+	return request.Do[*GetFxRateResponse](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("payins.quotes.GetFxRate", "/instances/{instance_id}/payin-quotes/fx"))
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/blindpaylabs/blindpay-go/internal/config"
 	"github.com/blindpaylabs/blindpay-go/internal/request"
 	"github.com/blindpaylabs/blindpay-go/internal/types"
+	"github.com/blindpaylabs/blindpay-go/pagination"
 )
 
 // Payout represents a payout transaction.
@@ -118,6 +119,7 @@ type CreateStellarParams struct {
 	QuoteID             string  `json:"quote_id"`
 	SenderWalletAddress string  `json:"sender_wallet_address"`
 	SignedTransaction   *string `json:"signed_transaction,omitempty"`
+	IdempotencyKey      string  `json:"-"`
 }
 
 // CreateSolanaParams represents parameters for creating a Solana payout.
@@ -125,12 +127,14 @@ type CreateSolanaParams struct {
 	QuoteID             string  `json:"quote_id"`
 	SenderWalletAddress string  `json:"sender_wallet_address"`
 	SignedTransaction   *string `json:"signed_transaction"`
+	IdempotencyKey      string  `json:"-"`
 }
 
 // CreateEvmParams represents parameters for creating an EVM payout.
 type CreateEvmParams struct {
 	QuoteID             string `json:"quote_id"`
 	SenderWalletAddress string `json:"sender_wallet_address"`
+	IdempotencyKey      string `json:"-"`
 }
 
 // SubmitDocumentsParams represents parameters for submitting payout documents.
@@ -156,6 +160,7 @@ type CreateResponse struct {
 	TrackingLiquidity   *types.TrackingLiquidity   `json:"tracking_liquidity,omitempty"`
 	TrackingComplete    *types.TrackingComplete    `json:"tracking_complete,omitempty"`
 	TrackingPartnerFee  *types.TrackingPartnerFee  `json:"tracking_partner_fee,omitempty"`
+	IdempotencyKey      string                     `json:"-"`
 }
 
 // Client handles payout-related operations.
@@ -192,11 +197,52 @@ func (c *Client) List(ctx context.Context, params *ListParams) (*ListResponse, e
 		}
 	}
 
-	return request.Do[*ListResponse](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*ListResponse](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("payouts.List", "/instances/{instance_id}/payouts"),
+		request.WithParams(params))
+}
+
+// ListAll returns an iterator over all the payouts matching params. It
+// fetches pages lazily, starting at params.Offset, and params.Limit serves as
+// the page size hint.
+func (c *Client) ListAll(ctx context.Context, params *ListParams) *pagination.Iterator[Payout] {
+	page := ListParams{}
+	if params != nil {
+		page = *params
+	}
+
+	return pagination.New(ctx, func(ctx context.Context) ([]Payout, bool, error) {
+		resp, err := c.List(ctx, &page)
+		if err != nil {
+			return nil, false, err
+		}
+		page.Offset += len(resp.Data)
+		return resp.Data, resp.Pagination.HasMore, nil
+	})
 }
 
 // Export retrieves all payouts for export with optional pagination.
 func (c *Client) Export(ctx context.Context, params *ExportParams) ([]Payout, error) {
+	return request.Do[[]Payout](c.cfg, ctx, "GET", c.exportPath(params), nil,
+		request.WithOperation("payouts.Export", "/instances/{instance_id}/export/payouts"),
+		request.WithParams(params))
+}
+
+// ExportEach streams the payouts of an export, calling fn with each payout as
+// it is decoded instead of buffering the whole response. An error returned by
+// fn stops the export and is returned as is.
+func (c *Client) ExportEach(ctx context.Context, params *ExportParams, fn func(Payout) error) error {
+	if fn == nil {
+		return request.NilParam("fn")
+	}
+
+	return request.Stream(c.cfg, ctx, "GET", c.exportPath(params), fn,
+		request.WithOperation("payouts.ExportEach", "/instances/{instance_id}/export/payouts"),
+		request.WithParams(params))
+}
+
+// exportPath returns the path of an export with the given params.
+func (c *Client) exportPath(params *ExportParams) string {
 	path := fmt.Sprintf("/instances/%s/export/payouts", c.instanceID)
 
 	if params != nil {
@@ -212,66 +258,101 @@ func (c *Client) Export(ctx context.Context, params *ExportParams) ([]Payout, er
 		}
 	}
 
-	return request.Do[[]Payout](c.cfg, ctx, "GET", path, nil)
+	return path
 }
 
 // Get retrieves a specific payout by ID.
 func (c *Client) Get(ctx context.Context, payoutID string) (*Payout, error) {
 	if payoutID == "" {
-		return nil, fmt.Errorf("payout ID cannot be empty")
+		return nil, request.EmptyParam("payout ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/payouts/%s", c.instanceID, payoutID)
-	return request.Do[*Payout](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*Payout](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("payouts.Get", "/instances/{instance_id}/payouts/{payout_id}"))
 }
 
 // GetTrack retrieves tracking information for a payout.
 func (c *Client) GetTrack(ctx context.Context, payoutID string) (*Payout, error) {
 	if payoutID == "" {
-		return nil, fmt.Errorf("payout ID cannot be empty")
+		return nil, request.EmptyParam("payout ID")
 	}
 
 	path := fmt.Sprintf("/e/payouts/%s", payoutID)
-	return request.Do[*Payout](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*Payout](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("payouts.GetTrack", "/e/payouts/{payout_id}"))
 }
 
 // CreateEvm creates an EVM payout.
 func (c *Client) CreateEvm(ctx context.Context, params *CreateEvmParams) (*CreateResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/payouts/evm", c.instanceID)
-	return request.Do[*CreateResponse](c.cfg, ctx, "POST", path, params)
+	var key string
+	resp, err := request.Do[*CreateResponse](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("payouts.CreateEvm", "/instances/{instance_id}/payouts/evm"),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // CreateStellar creates a Stellar payout.
 func (c *Client) CreateStellar(ctx context.Context, params *CreateStellarParams) (*CreateResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/payouts/stellar", c.instanceID)
-	return request.Do[*CreateResponse](c.cfg, ctx, "POST", path, params)
+	var key string
+	resp, err := request.Do[*CreateResponse](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("payouts.CreateStellar", "/instances/{instance_id}/payouts/stellar"),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // CreateSolana creates a Solana payout.
 func (c *Client) CreateSolana(ctx context.Context, params *CreateSolanaParams) (*CreateResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/payouts/solana", c.instanceID)
-	return request.Do[*CreateResponse](c.cfg, ctx, "POST", path, params)
+	var key string
+	resp, err := request.Do[*CreateResponse](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("payouts.CreateSolana", "/instances/{instance_id}/payouts/solana"),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // SubmitDocuments submits documents for a payout.
 func (c *Client) SubmitDocuments(ctx context.Context, params *SubmitDocumentsParams) (*SubmitDocumentsResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.PayoutID == "" {
-		return nil, fmt.Errorf("payout ID cannot be empty")
+		return nil, request.EmptyParam("payout ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/payouts/%s/documents", c.instanceID, params.PayoutID)
@@ -286,15 +367,18 @@ func (c *Client) SubmitDocuments(ctx context.Context, params *SubmitDocumentsPar
 		body["description"] = params.Description
 	}
 
-	return request.Do[*SubmitDocumentsResponse](c.cfg, ctx, "POST", path, body)
+	return request.Do[*SubmitDocumentsResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("payouts.SubmitDocuments", "/instances/{instance_id}/payouts/{payout_id}/documents"),
+		request.WithParams(params))
 }
 
 // AuthorizeStellarToken authorizes a Stellar token for payout.
 func (c *Client) AuthorizeStellarToken(ctx context.Context, params *AuthorizeStellarTokenParams) (*AuthorizeStellarTokenResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/payouts/stellar/authorize-token", c.instanceID)
-	return request.Do[*AuthorizeStellarTokenResponse](c.cfg, ctx, "POST", path, params)
+	return request.Do[*AuthorizeStellarTokenResponse](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("payouts.AuthorizeStellarToken", "/instances/{instance_id}/payouts/stellar/authorize-token"))
 }
@@ -3,13 +3,16 @@ package payouts
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
 
 	"github.com/blindpaylabs/blindpay-go/internal/blindpaytest"
 	"github.com/blindpaylabs/blindpay-go/internal/config"
+	"github.com/blindpaylabs/blindpay-go/internal/request"
 	"github.com/blindpaylabs/blindpay-go/internal/types"
+	"github.com/blindpaylabs/blindpay-go/pagination"
 	"github.com/stretchr/testify/require"
 )
 
@@ -328,6 +331,48 @@ func TestPayouts_Export(t *testing.T) {
 	require.Equal(t, types.TransactionStatusCompleted, payouts[1].Status)
 }
 
+func TestPayouts_ExportEach(t *testing.T) {
+	instanceID := "in_000000000000"
+
+	cfg := &config.Config{
+		BaseURL:    "https://api.blindpay.com",
+		APIKey:     "test_key",
+		InstanceID: instanceID,
+		HTTPClient: &http.Client{
+			Transport: &blindpaytest.RoundTripper{
+				T: t,
+				Out: json.RawMessage(`[
+					{"id":"pa_000000000000","status":"processing","receiver_id":"re_000000000000"},
+					{"id":"pa_111111111111","status":"completed","receiver_id":"re_111111111111"},
+					{"id":"pa_222222222222","status":"failed","receiver_id":"re_222222222222"}
+				]`),
+				Method: http.MethodGet,
+				Path:   fmt.Sprintf("/instances/%s/export/payouts", instanceID),
+			},
+		},
+		UserAgent: "test",
+	}
+
+	client := NewClient(cfg)
+
+	var ids []string
+	err := client.ExportEach(context.Background(), &ExportParams{Limit: 100}, func(payout Payout) error {
+		ids = append(ids, payout.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"pa_000000000000", "pa_111111111111", "pa_222222222222"}, ids)
+
+	stop := errors.New("stop")
+	ids = nil
+	err = client.ExportEach(context.Background(), nil, func(payout Payout) error {
+		ids = append(ids, payout.ID)
+		return stop
+	})
+	require.Same(t, stop, err)
+	require.Equal(t, []string{"pa_000000000000"}, ids)
+}
+
 func TestPayouts_Get(t *testing.T) {
 	instanceID := "in_000000000000"
 	id := "pa_000000000000"
@@ -618,3 +663,102 @@ func TestPayouts_AuthorizeStellarToken(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "string", response.TransactionHash)
 }
+
+func TestPayouts_CreateEvm_IdempotencyKey(t *testing.T) {
+	instanceID := "in_000000000000"
+
+	newClient := func(rt *blindpaytest.RoundTripper) *Client {
+		return NewClient(&config.Config{
+			BaseURL:    "https://api.blindpay.com",
+			APIKey:     "test_key",
+			InstanceID: instanceID,
+			HTTPClient: &http.Client{Transport: rt},
+			UserAgent:  "test",
+		})
+	}
+
+	t.Run("uses the caller key", func(t *testing.T) {
+		rt := &blindpaytest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(`{"id":"pa_000000000000","status":"processing"}`),
+			Method: http.MethodPost,
+		}
+
+		payout, err := newClient(rt).CreateEvm(context.Background(), &CreateEvmParams{
+			QuoteID:             "qu_000000000000",
+			SenderWalletAddress: "0x123...890",
+			IdempotencyKey:      "payout-42",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "payout-42", payout.IdempotencyKey)
+		require.Len(t, rt.Requests, 1)
+		require.Equal(t, "payout-42", rt.Requests[0].Header.Get("Idempotency-Key"))
+	})
+
+	t.Run("generates a key", func(t *testing.T) {
+		rt := &blindpaytest.RoundTripper{
+			T:      t,
+			In:     json.RawMessage(`{"quote_id":"qu_000000000000","sender_wallet_address":"0x123...890"}`),
+			Out:    json.RawMessage(`{"id":"pa_000000000000","status":"processing"}`),
+			Method: http.MethodPost,
+		}
+
+		payout, err := newClient(rt).CreateEvm(context.Background(), &CreateEvmParams{
+			QuoteID:             "qu_000000000000",
+			SenderWalletAddress: "0x123...890",
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, payout.IdempotencyKey)
+		require.Equal(t, payout.IdempotencyKey, rt.Requests[0].Header.Get("Idempotency-Key"))
+	})
+
+	t.Run("reports the key of a failed create", func(t *testing.T) {
+		rt := &blindpaytest.RoundTripper{
+			T:      t,
+			Out:    json.RawMessage(`{"message":"gateway timeout"}`),
+			Method: http.MethodPost,
+			Status: http.StatusGatewayTimeout,
+		}
+
+		_, err := newClient(rt).CreateEvm(context.Background(), &CreateEvmParams{
+			QuoteID:             "qu_000000000000",
+			SenderWalletAddress: "0x123...890",
+		})
+		var createErr *request.CreateError
+		require.ErrorAs(t, err, &createErr)
+		require.NotEmpty(t, createErr.IdempotencyKey)
+		require.Equal(t, createErr.IdempotencyKey, rt.Requests[0].Header.Get("Idempotency-Key"))
+	})
+}
+
+func TestPayouts_ListAll(t *testing.T) {
+	instanceID := "in_000000000000"
+
+	transport := &blindpaytest.RoundTripper{
+		T: t,
+		Outs: []json.RawMessage{
+			json.RawMessage(`{"data":[{"id":"pa_000000000000"},{"id":"pa_111111111111"}],"pagination":{"has_more":true}}`),
+			json.RawMessage(`{"data":[{"id":"pa_222222222222"}],"pagination":{"has_more":false}}`),
+		},
+		Method: http.MethodGet,
+		Path:   fmt.Sprintf("/instances/%s/payouts", instanceID),
+	}
+
+	cfg := &config.Config{
+		BaseURL:    "https://api.blindpay.com",
+		APIKey:     "test_key",
+		InstanceID: instanceID,
+		HTTPClient: &http.Client{Transport: transport},
+		UserAgent:  "test",
+	}
+
+	client := NewClient(cfg)
+	payouts, err := pagination.Collect(client.ListAll(context.Background(), &ListParams{ReceiverID: "re_000000000000", Limit: 2}))
+	require.NoError(t, err)
+	require.Len(t, payouts, 3)
+	require.Equal(t, "pa_222222222222", payouts[2].ID)
+
+	require.Len(t, transport.Requests, 2)
+	require.Equal(t, "limit=2&receiver_id=re_000000000000", transport.Requests[0].URL.RawQuery)
+	require.Equal(t, "limit=2&offset=2&receiver_id=re_000000000000", transport.Requests[1].URL.RawQuery)
+}
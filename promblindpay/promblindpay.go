@@ -0,0 +1,160 @@
+// Package promblindpay provides a Prometheus collector for BlindPay client metrics.
+//
+//	collector := promblindpay.NewCollector()
+//	prometheus.MustRegister(collector)
+//
+//	client, err := blindpay.New(apiKey, instanceID,
+//		blindpay.WithMetrics(collector),
+//	)
+//
+// It is versioned as a separate module, which keeps the Prometheus client out
+// of the dependencies of the SDK itself:
+//
+//	go get github.com/blindpaylabs/blindpay-go/promblindpay
+package promblindpay
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/blindpaylabs/blindpay-go"
+)
+
+// Collector records BlindPay client metrics and exposes them to Prometheus.
+//
+// It implements both blindpay.Metrics and prometheus.Collector.
+type Collector struct {
+	requests      *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	retries       *prometheus.CounterVec
+	rateLimited   *prometheus.CounterVec
+	verifications *prometheus.CounterVec
+}
+
+type config struct {
+	namespace   string
+	constLabels prometheus.Labels
+	buckets     []float64
+}
+
+// Option configures a Collector.
+type Option func(*config)
+
+// WithNamespace sets the metric namespace. It defaults to "blindpay".
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithConstLabels adds constant labels to every metric, such as the environment.
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(c *config) {
+		c.constLabels = labels
+	}
+}
+
+// WithBuckets sets the buckets of the request duration histogram, in seconds.
+func WithBuckets(buckets []float64) Option {
+	return func(c *config) {
+		if len(buckets) > 0 {
+			c.buckets = buckets
+		}
+	}
+}
+
+// NewCollector creates a new Collector. It must be registered with a
+// Prometheus registry to be scraped.
+func NewCollector(opts ...Option) *Collector {
+	cfg := &config{
+		namespace: "blindpay",
+		buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Name:        "requests_total",
+			Help:        "Number of BlindPay API calls by operation and status class.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"operation", "status_class"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   cfg.namespace,
+			Name:        "request_duration_seconds",
+			Help:        "Latency of BlindPay API calls, including retries, by operation and status class.",
+			ConstLabels: cfg.constLabels,
+			Buckets:     cfg.buckets,
+		}, []string{"operation", "status_class"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Name:        "retries_total",
+			Help:        "Number of retried BlindPay API attempts by operation and the status that caused the retry.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"operation", "status"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Name:        "rate_limited_total",
+			Help:        "Number of 429 responses received from the BlindPay API by operation.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"operation"}),
+		verifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Name:        "webhook_verifications_total",
+			Help:        "Number of BlindPay webhook signature verifications by outcome.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"outcome"}),
+	}
+}
+
+var (
+	_ blindpay.Metrics     = (*Collector)(nil)
+	_ prometheus.Collector = (*Collector)(nil)
+)
+
+// ObserveRequest implements blindpay.Metrics.
+func (c *Collector) ObserveRequest(operation, statusClass string, duration time.Duration) {
+	c.requests.WithLabelValues(operation, statusClass).Inc()
+	c.duration.WithLabelValues(operation, statusClass).Observe(duration.Seconds())
+}
+
+// ObserveRetry implements blindpay.Metrics.
+func (c *Collector) ObserveRetry(operation string, status int) {
+	label := "error"
+	if status != 0 {
+		label = strconv.Itoa(status)
+	}
+	c.retries.WithLabelValues(operation, label).Inc()
+}
+
+// ObserveRateLimited implements blindpay.Metrics.
+func (c *Collector) ObserveRateLimited(operation string) {
+	c.rateLimited.WithLabelValues(operation).Inc()
+}
+
+// ObserveWebhookVerification implements blindpay.Metrics.
+func (c *Collector) ObserveWebhookVerification(outcome string) {
+	c.verifications.WithLabelValues(outcome).Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.duration.Describe(ch)
+	c.retries.Describe(ch)
+	c.rateLimited.Describe(ch)
+	c.verifications.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.duration.Collect(ch)
+	c.retries.Collect(ch)
+	c.rateLimited.Collect(ch)
+	c.verifications.Collect(ch)
+}
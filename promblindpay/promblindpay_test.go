@@ -0,0 +1,58 @@
+package promblindpay
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blindpaylabs/blindpay-go"
+	"github.com/blindpaylabs/blindpay-go/internal/blindpaytest"
+)
+
+func TestCollector_RecordsClientMetrics(t *testing.T) {
+	collector := NewCollector()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	policy := blindpay.DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+
+	client, err := blindpay.New("test_key", "in_000000000000",
+		blindpay.WithHTTPClient(&http.Client{Transport: &blindpaytest.RoundTripper{
+			T:      t,
+			Status: http.StatusTooManyRequests,
+			Out:    json.RawMessage(`{"message":"too many requests"}`),
+		}}),
+		blindpay.WithRetryPolicy(policy),
+		blindpay.WithMetrics(collector),
+	)
+	require.NoError(t, err)
+
+	_, err = client.Available.GetRails(context.Background())
+	require.Error(t, err)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(collector.requests.WithLabelValues("available.GetRails", "4xx")))
+	require.Equal(t, 2.0, testutil.ToFloat64(collector.retries.WithLabelValues("available.GetRails", "429")))
+	require.Equal(t, 3.0, testutil.ToFloat64(collector.rateLimited.WithLabelValues("available.GetRails")))
+
+	client.VerifyWebhookSignature("whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw", "msg_1", "1", "{}", "v1,invalid")
+	require.Equal(t, 1.0, testutil.ToFloat64(collector.verifications.WithLabelValues(blindpay.WebhookVerificationInvalid)))
+
+	count, err := testutil.GatherAndCount(registry, "blindpay_request_duration_seconds")
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	expected := `
+# HELP blindpay_rate_limited_total Number of 429 responses received from the BlindPay API by operation.
+# TYPE blindpay_rate_limited_total counter
+blindpay_rate_limited_total{operation="available.GetRails"} 3
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "blindpay_rate_limited_total"))
+}
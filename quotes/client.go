@@ -22,6 +22,7 @@ type CreateParams struct {
 	TransactionDocumentFile *string                       `json:"transaction_document_file"`
 	TransactionDocumentID   *string                       `json:"transaction_document_id"`
 	TransactionDocumentType types.TransactionDocumentType `json:"transaction_document_type"`
+	IdempotencyKey          string                        `json:"-"`
 }
 
 // QuoteContract represents contract information in a quote.
@@ -50,6 +51,7 @@ type CreateResponse struct {
 	Contract            QuoteContract `json:"contract"`
 	ReceiverLocalAmount float64       `json:"receiver_local_amount"`
 	Description         string        `json:"description"`
+	IdempotencyKey      string        `json:"-"`
 }
 
 // GetFxRateParams represents parameters for getting FX rates.
@@ -86,19 +88,31 @@ func NewClient(cfg *config.Config) *Client {
 // Create creates a new quote for a payout.
 func (c *Client) Create(ctx context.Context, params *CreateParams) (*CreateResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/quotes", c.instanceID)
-	return request.Do[*CreateResponse](c.cfg, ctx, "POST", path, params)
+	var key string
+	resp, err := request.Do[*CreateResponse](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("quotes.Create", "/instances/{instance_id}/quotes"),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // GetFxRate retrieves the current FX rate for a currency pair.
 func (c *Client) GetFxRate(ctx context.Context, params *GetFxRateParams) (*GetFxRateResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/quotes/fx", c.instanceID)
-	return request.Do[*GetFxRateResponse](c.cfg, ctx, "POST", path, params)
+	return request.Do[*GetFxRateResponse](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("quotes.GetFxRate", "/instances/{instance_id}/quotes/fx"))
 }
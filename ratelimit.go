@@ -0,0 +1,9 @@
+package blindpay
+
+import (
+	"github.com/blindpaylabs/blindpay-go/internal/request"
+)
+
+// RateLimit configures the client-side rate limiter set with WithRateLimit.
+// It is an alias to the internal request.RateLimit for convenience.
+type RateLimit = request.RateLimit
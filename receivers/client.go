@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/blindpaylabs/blindpay-go/internal/config"
 	"github.com/blindpaylabs/blindpay-go/internal/request"
 	"github.com/blindpaylabs/blindpay-go/internal/types"
+	"github.com/blindpaylabs/blindpay-go/pagination"
 )
 
 // ProofOfAddressDocType represents proof of address document types.
@@ -346,6 +348,7 @@ type CreateIndividualStandardParams struct {
 	Occupation            *string                      `json:"occupation,omitempty"`
 	RecipientRelationship *types.RecipientRelationship `json:"recipient_relationship,omitempty"`
 	SoleProprietorDocType *types.SoleProprietorDocType `json:"sole_proprietor_doc_type,omitempty"`
+	IdempotencyKey        string                       `json:"-"`
 }
 
 // CreateIndividualEnhancedParams represents parameters for creating an individual with enhanced KYC.
@@ -380,6 +383,7 @@ type CreateIndividualEnhancedParams struct {
 	Occupation                       *string                      `json:"occupation,omitempty"`
 	RecipientRelationship            *types.RecipientRelationship `json:"recipient_relationship,omitempty"`
 	SoleProprietorDocType            *types.SoleProprietorDocType `json:"sole_proprietor_doc_type,omitempty"`
+	IdempotencyKey                   string                       `json:"-"`
 }
 
 // CreateBusinessStandardParams represents parameters for creating a business with standard KYB.
@@ -416,11 +420,13 @@ type CreateBusinessStandardParams struct {
 	PubliclyTraded          *bool                        `json:"publicly_traded,omitempty"`
 	RecipientRelationship   *types.RecipientRelationship `json:"recipient_relationship,omitempty"`
 	SoleProprietorDocType   *types.SoleProprietorDocType `json:"sole_proprietor_doc_type,omitempty"`
+	IdempotencyKey          string                       `json:"-"`
 }
 
 // CreateResponse represents the response when creating a receiver.
 type CreateResponse struct {
-	ID string `json:"id"`
+	ID             string `json:"id"`
+	IdempotencyKey string `json:"-"`
 }
 
 // UpdateParams represents parameters for updating a receiver.
@@ -556,7 +562,8 @@ func NewClient(cfg *config.Config) *Client {
 // List retrieves all receivers for the instance.
 func (c *Client) List(ctx context.Context) ([]Receiver, error) {
 	path := fmt.Sprintf("/instances/%s/receivers", c.instanceID)
-	return request.Do[[]Receiver](c.cfg, ctx, "GET", path, nil)
+	return request.Do[[]Receiver](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("receivers.List", "/instances/{instance_id}/receivers"))
 }
 
 // ListWithParams retrieves receivers with pagination and filtering.
@@ -600,13 +607,60 @@ func (c *Client) ListWithParams(ctx context.Context, params *ListParams) (*ListR
 		}
 	}
 
-	return request.Do[*ListResponse](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*ListResponse](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("receivers.ListWithParams", "/instances/{instance_id}/receivers"),
+		request.WithParams(params))
+}
+
+// ListAll returns an iterator over all the receivers matching params, fetched
+// lazily with ListWithParams. params.Limit serves as the page size hint.
+//
+// Pages follow each other by offset, starting at params.Offset, unless
+// params.StartingAfter or params.EndingBefore is set: the iterator then walks
+// forward from StartingAfter, or backward from EndingBefore, using the ID of
+// the last or, going backward, the first receiver of each page as the next
+// cursor.
+func (c *Client) ListAll(ctx context.Context, params *ListParams) *pagination.Iterator[Receiver] {
+	page := ListParams{}
+	if params != nil {
+		page = *params
+	}
+
+	offset := 0
+	if page.Offset != "" {
+		var err error
+		if offset, err = strconv.Atoi(page.Offset); err != nil {
+			return pagination.New(ctx, func(ctx context.Context) ([]Receiver, bool, error) {
+				return nil, false, &request.ParamError{Param: "offset", Reason: "must be an integer"}
+			})
+		}
+	}
+
+	return pagination.New(ctx, func(ctx context.Context) ([]Receiver, bool, error) {
+		resp, err := c.ListWithParams(ctx, &page)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if n := len(resp.Data); n > 0 {
+			switch {
+			case page.EndingBefore != "":
+				page.EndingBefore = resp.Data[0].ID
+			case page.StartingAfter != "":
+				page.StartingAfter = resp.Data[n-1].ID
+			default:
+				offset += n
+				page.Offset = strconv.Itoa(offset)
+			}
+		}
+		return resp.Data, resp.Pagination.HasMore, nil
+	})
 }
 
 // CreateIndividualWithStandardKYC creates an individual receiver with standard KYC.
 func (c *Client) CreateIndividualWithStandardKYC(ctx context.Context, params *CreateIndividualStandardParams) (*CreateResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers", c.instanceID)
@@ -660,13 +714,25 @@ func (c *Client) CreateIndividualWithStandardKYC(ctx context.Context, params *Cr
 		body["sole_proprietor_doc_type"] = params.SoleProprietorDocType
 	}
 
-	return request.Do[*CreateResponse](c.cfg, ctx, "POST", path, body)
+	var key string
+	resp, err := request.Do[*CreateResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("receivers.CreateIndividualWithStandardKYC", "/instances/{instance_id}/receivers"),
+		request.WithParams(params),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // CreateIndividualWithEnhancedKYC creates an individual receiver with enhanced KYC.
 func (c *Client) CreateIndividualWithEnhancedKYC(ctx context.Context, params *CreateIndividualEnhancedParams) (*CreateResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers", c.instanceID)
@@ -727,13 +793,25 @@ func (c *Client) CreateIndividualWithEnhancedKYC(ctx context.Context, params *Cr
 		body["sole_proprietor_doc_type"] = params.SoleProprietorDocType
 	}
 
-	return request.Do[*CreateResponse](c.cfg, ctx, "POST", path, body)
+	var key string
+	resp, err := request.Do[*CreateResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("receivers.CreateIndividualWithEnhancedKYC", "/instances/{instance_id}/receivers"),
+		request.WithParams(params),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // CreateBusinessWithStandardKYB creates a business receiver with standard KYB.
 func (c *Client) CreateBusinessWithStandardKYB(ctx context.Context, params *CreateBusinessStandardParams) (*CreateResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers", c.instanceID)
@@ -808,26 +886,39 @@ func (c *Client) CreateBusinessWithStandardKYB(ctx context.Context, params *Crea
 		body["sole_proprietor_doc_type"] = params.SoleProprietorDocType
 	}
 
-	return request.Do[*CreateResponse](c.cfg, ctx, "POST", path, body)
+	var key string
+	resp, err := request.Do[*CreateResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("receivers.CreateBusinessWithStandardKYB", "/instances/{instance_id}/receivers"),
+		request.WithParams(params),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // Get retrieves a specific receiver by ID.
 func (c *Client) Get(ctx context.Context, receiverID string) (*Receiver, error) {
 	if receiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s", c.instanceID, receiverID)
-	return request.Do[*Receiver](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*Receiver](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("receivers.Get", "/instances/{instance_id}/receivers/{receiver_id}"))
 }
 
 // Update updates a receiver.
 func (c *Client) Update(ctx context.Context, params *UpdateParams) error {
 	if params == nil {
-		return fmt.Errorf("params cannot be nil")
+		return request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return fmt.Errorf("receiver ID cannot be empty")
+		return request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s", c.instanceID, params.ReceiverID)
@@ -971,48 +1062,53 @@ func (c *Client) Update(ctx context.Context, params *UpdateParams) error {
 		body["sole_proprietor_doc_type"] = params.SoleProprietorDocType
 	}
 
-	_, err := request.Do[struct{}](c.cfg, ctx, "PUT", path, body)
+	_, err := request.Do[struct{}](c.cfg, ctx, "PUT", path, body,
+		request.WithOperation("receivers.Update", "/instances/{instance_id}/receivers/{receiver_id}"),
+		request.WithParams(params))
 	return err
 }
 
 // Delete deletes a receiver.
 func (c *Client) Delete(ctx context.Context, receiverID string) error {
 	if receiverID == "" {
-		return fmt.Errorf("receiver ID cannot be empty")
+		return request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s", c.instanceID, receiverID)
-	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil)
+	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil,
+		request.WithOperation("receivers.Delete", "/instances/{instance_id}/receivers/{receiver_id}"))
 	return err
 }
 
 // GetLimits retrieves transaction limits for a receiver.
 func (c *Client) GetLimits(ctx context.Context, receiverID string) (*LimitsResponse, error) {
 	if receiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/limits/receivers/%s", c.instanceID, receiverID)
-	return request.Do[*LimitsResponse](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*LimitsResponse](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("receivers.GetLimits", "/instances/{instance_id}/limits/receivers/{receiver_id}"))
 }
 
 // GetLimitIncreaseRequests retrieves all limit increase requests for a receiver.
 func (c *Client) GetLimitIncreaseRequests(ctx context.Context, receiverID string) ([]LimitIncreaseRequest, error) {
 	if receiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/limit-increase", c.instanceID, receiverID)
-	return request.Do[[]LimitIncreaseRequest](c.cfg, ctx, "GET", path, nil)
+	return request.Do[[]LimitIncreaseRequest](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("receivers.GetLimitIncreaseRequests", "/instances/{instance_id}/receivers/{receiver_id}/limit-increase"))
 }
 
 // RequestLimitIncrease creates a new limit increase request for a receiver.
 func (c *Client) RequestLimitIncrease(ctx context.Context, params *RequestLimitIncreaseParams) (*RequestLimitIncreaseResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/limit-increase", c.instanceID, params.ReceiverID)
@@ -1025,5 +1121,7 @@ func (c *Client) RequestLimitIncrease(ctx context.Context, params *RequestLimitI
 		"supporting_document_type": params.SupportingDocumentType,
 	}
 
-	return request.Do[*RequestLimitIncreaseResponse](c.cfg, ctx, "POST", path, body)
+	return request.Do[*RequestLimitIncreaseResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("receivers.RequestLimitIncrease", "/instances/{instance_id}/receivers/{receiver_id}/limit-increase"),
+		request.WithParams(params))
 }
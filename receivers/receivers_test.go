@@ -10,6 +10,7 @@ import (
 	"github.com/blindpaylabs/blindpay-go/internal/blindpaytest"
 	"github.com/blindpaylabs/blindpay-go/internal/config"
 	"github.com/blindpaylabs/blindpay-go/internal/types"
+	"github.com/blindpaylabs/blindpay-go/pagination"
 	"github.com/stretchr/testify/require"
 )
 
@@ -681,3 +682,39 @@ func TestReceivers_ListWithParams(t *testing.T) {
 	require.Equal(t, "re_Euw7HN4OdxPn", result.Data[0].ID)
 	require.Equal(t, false, result.Pagination.HasMore)
 }
+
+func TestReceivers_ListAllWithCursor(t *testing.T) {
+	instanceID := "in_000000000000"
+
+	transport := &blindpaytest.RoundTripper{
+		T: t,
+		Outs: []json.RawMessage{
+			json.RawMessage(`{"data":[{"id":"re_111111111111"},{"id":"re_222222222222"}],"pagination":{"has_more":true}}`),
+			json.RawMessage(`{"data":[{"id":"re_333333333333"}],"pagination":{"has_more":false}}`),
+		},
+		Method: http.MethodGet,
+		Path:   fmt.Sprintf("/instances/%s/receivers", instanceID),
+	}
+
+	cfg := &config.Config{
+		BaseURL:    "https://api.blindpay.com",
+		APIKey:     "test_key",
+		InstanceID: instanceID,
+		HTTPClient: &http.Client{Transport: transport},
+		UserAgent:  "test",
+	}
+
+	client := NewClient(cfg)
+
+	var ids []string
+	err := pagination.ForEach(client.ListAll(context.Background(), &ListParams{Limit: "2", StartingAfter: "re_000000000000"}), func(receiver Receiver) error {
+		ids = append(ids, receiver.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"re_111111111111", "re_222222222222", "re_333333333333"}, ids)
+
+	require.Len(t, transport.Requests, 2)
+	require.Equal(t, "re_000000000000", transport.Requests[0].URL.Query().Get("starting_after"))
+	require.Equal(t, "re_222222222222", transport.Requests[1].URL.Query().Get("starting_after"))
+}
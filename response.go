@@ -0,0 +1,29 @@
+package blindpay
+
+import (
+	"context"
+
+	"github.com/blindpaylabs/blindpay-go/internal/request"
+)
+
+// ResponseMeta describes the HTTP exchange behind an API call: status,
+// headers, raw body, duration, attempts, request identifiers and rate-limit state.
+// It is an alias to the internal request.ResponseMeta for convenience.
+type ResponseMeta = request.ResponseMeta
+
+// RateLimitState is the rate-limit window reported by the API.
+// It is an alias to the internal request.RateLimitState for convenience.
+type RateLimitState = request.RateLimitState
+
+// WithResponseCapture returns a context that makes API calls fill in meta,
+// on success as well as on failure:
+//
+//	var meta blindpay.ResponseMeta
+//	payout, err := client.Payouts.CreateEvm(blindpay.WithResponseCapture(ctx, &meta), params)
+//	log.Println(meta.RequestID, meta.Attempts)
+//
+// When several calls, concurrent or not, share the context, meta describes
+// the last one to complete. Read it once they are all done.
+func WithResponseCapture(ctx context.Context, meta *ResponseMeta) context.Context {
+	return request.WithResponseCapture(ctx, meta)
+}
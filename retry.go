@@ -0,0 +1,16 @@
+package blindpay
+
+import (
+	"github.com/blindpaylabs/blindpay-go/internal/request"
+)
+
+// RetryPolicy controls how failed requests are retried.
+// It is an alias to the internal request.RetryPolicy for convenience.
+type RetryPolicy = request.RetryPolicy
+
+// DefaultRetryPolicy returns the recommended retry policy, to be given to
+// WithRetryPolicy: up to 3 attempts with exponential backoff on 429, 502, 503
+// and 504 responses, honoring the Retry-After header.
+func DefaultRetryPolicy() RetryPolicy {
+	return request.DefaultRetryPolicy()
+}
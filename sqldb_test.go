@@ -0,0 +1,213 @@
+package blindpay
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// sqlDB is a database/sql driver understanding the queries of SQLDedupeStore
+// and SQLWebhookQueueStore, keeping its rows in memory. It evaluates the
+// simple INSERT, UPDATE, DELETE and SELECT statements of the stores, whose
+// conditions are joined with AND, and rejects the insert of an existing id.
+type sqlDB struct {
+	mu   sync.Mutex
+	rows []map[string]driver.Value
+}
+
+var (
+	sqlInsert = regexp.MustCompile(`^INSERT INTO \w+ \((.+)\) VALUES`)
+	sqlUpdate = regexp.MustCompile(`^UPDATE \w+ SET (.+) WHERE (.+)$`)
+	sqlDelete = regexp.MustCompile(`^DELETE FROM \w+ WHERE (.+)$`)
+	sqlSelect = regexp.MustCompile(`^SELECT (.+) FROM \w+ WHERE (.+?)(?: ORDER BY (\w+))?(?: LIMIT (\d+))?$`)
+	sqlCond   = regexp.MustCompile(`^(\w+) (=|<=|>=|<|IN) (\?|\(.+\))$`)
+)
+
+func openSQLDB(t *testing.T) *sql.DB {
+	db := sql.OpenDB(&sqlDB{})
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func (d *sqlDB) Connect(context.Context) (driver.Conn, error) { return d, nil }
+func (d *sqlDB) Driver() driver.Driver                        { return nil }
+func (d *sqlDB) Prepare(string) (driver.Stmt, error)          { return nil, errors.New("not supported") }
+func (d *sqlDB) Close() error                                 { return nil }
+func (d *sqlDB) Begin() (driver.Tx, error)                    { return nil, errors.New("not supported") }
+
+func (d *sqlDB) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+
+	switch {
+	case strings.HasPrefix(query, "CREATE TABLE"):
+		return driver.RowsAffected(0), nil
+	case sqlInsert.MatchString(query):
+		row := make(map[string]driver.Value)
+		for i, column := range strings.Split(sqlInsert.FindStringSubmatch(query)[1], ", ") {
+			row[column] = values[i]
+		}
+		for _, existing := range d.rows {
+			if existing["id"] == row["id"] {
+				return nil, errors.New("duplicate key")
+			}
+		}
+		d.rows = append(d.rows, row)
+		return driver.RowsAffected(1), nil
+	case sqlUpdate.MatchString(query):
+		m := sqlUpdate.FindStringSubmatch(query)
+		sets := strings.Split(m[1], ", ")
+		match, err := sqlWhere(m[2], values[len(sets):])
+		if err != nil {
+			return nil, err
+		}
+		var n int64
+		for _, row := range d.rows {
+			if match(row) {
+				for i, set := range sets {
+					row[strings.TrimSuffix(set, " = ?")] = values[i]
+				}
+				n++
+			}
+		}
+		return driver.RowsAffected(n), nil
+	case sqlDelete.MatchString(query):
+		match, err := sqlWhere(sqlDelete.FindStringSubmatch(query)[1], values)
+		if err != nil {
+			return nil, err
+		}
+		kept := d.rows[:0]
+		for _, row := range d.rows {
+			if !match(row) {
+				kept = append(kept, row)
+			}
+		}
+		n := int64(len(d.rows) - len(kept))
+		d.rows = kept
+		return driver.RowsAffected(n), nil
+	}
+	return nil, fmt.Errorf("unexpected query %q", query)
+}
+
+func (d *sqlDB) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	m := sqlSelect.FindStringSubmatch(query)
+	if m == nil {
+		return nil, fmt.Errorf("unexpected query %q", query)
+	}
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	match, err := sqlWhere(m[2], values)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []map[string]driver.Value
+	for _, row := range d.rows {
+		if match(row) {
+			selected = append(selected, row)
+		}
+	}
+	if m[3] != "" {
+		sort.SliceStable(selected, func(i, j int) bool {
+			return selected[i][m[3]].(int64) < selected[j][m[3]].(int64)
+		})
+	}
+	if m[4] != "" {
+		if limit, _ := strconv.Atoi(m[4]); len(selected) > limit {
+			selected = selected[:limit]
+		}
+	}
+
+	rows := &sqlRows{columns: strings.Split(m[1], ", ")}
+	for _, row := range selected {
+		out := make([]driver.Value, len(rows.columns))
+		for i, column := range rows.columns {
+			out[i] = row[column]
+			if column == "1" {
+				out[i] = int64(1)
+			}
+		}
+		rows.values = append(rows.values, out)
+	}
+	return rows, nil
+}
+
+// sqlWhere returns a function matching the rows selected by the conditions
+// of a WHERE clause, consuming args.
+func sqlWhere(clause string, args []driver.Value) (func(map[string]driver.Value) bool, error) {
+	var conds []func(map[string]driver.Value) bool
+	for _, cond := range strings.Split(clause, " AND ") {
+		m := sqlCond.FindStringSubmatch(cond)
+		if m == nil {
+			return nil, fmt.Errorf("unexpected condition %q", cond)
+		}
+		column, op := m[1], m[2]
+		n := strings.Count(m[3], "?")
+		operands := args[:n]
+		args = args[n:]
+		conds = append(conds, func(row map[string]driver.Value) bool {
+			value := row[column]
+			switch op {
+			case "=":
+				return value == operands[0]
+			case "IN":
+				for _, operand := range operands {
+					if value == operand {
+						return true
+					}
+				}
+				return false
+			case "<=":
+				return value.(int64) <= operands[0].(int64)
+			case ">=":
+				return value.(int64) >= operands[0].(int64)
+			default:
+				return value.(int64) < operands[0].(int64)
+			}
+		})
+	}
+	return func(row map[string]driver.Value) bool {
+		for _, cond := range conds {
+			if !cond(row) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+type sqlRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *sqlRows) Columns() []string { return r.columns }
+func (r *sqlRows) Close() error      { return nil }
+
+func (r *sqlRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
@@ -37,12 +37,13 @@ func NewClient(cfg *config.Config) *Client {
 // Initiate initiates the terms of service flow and returns a URL.
 func (c *Client) Initiate(ctx context.Context, params *InitiateParams) (*InitiateResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.IdempotencyKey == "" {
-		return nil, fmt.Errorf("idempotency key cannot be empty")
+		return nil, request.EmptyParam("idempotency key")
 	}
 
 	path := fmt.Sprintf("/e/instances/%s/tos", c.instanceID)
-	return request.Do[*InitiateResponse](c.cfg, ctx, "POST", path, params)
+	return request.Do[*InitiateResponse](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("termsofservice.Initiate", "/e/instances/{instance_id}/tos"))
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/blindpaylabs/blindpay-go/internal/config"
 	"github.com/blindpaylabs/blindpay-go/internal/request"
 	"github.com/blindpaylabs/blindpay-go/internal/types"
+	"github.com/blindpaylabs/blindpay-go/pagination"
 )
 
 // TrackingStep represents a transfer tracking step.
@@ -87,6 +88,7 @@ type CreateQuoteResponse struct {
 // CreateParams represents parameters for creating a transfer.
 type CreateParams struct {
 	TransferQuoteID string `json:"transfer_quote_id"`
+	IdempotencyKey  string `json:"-"`
 }
 
 // CreateResponse represents the response when creating a transfer.
@@ -98,6 +100,7 @@ type CreateResponse struct {
 	TrackingPaymaster             TrackingStep                  `json:"tracking_paymaster"`
 	TrackingTransactionMonitoring TrackingTransactionMonitoring `json:"tracking_transaction_monitoring"`
 	TrackingPartnerFee            TrackingStep                  `json:"tracking_partner_fee"`
+	IdempotencyKey                string                        `json:"-"`
 }
 
 // ListParams represents parameters for listing transfers.
@@ -141,21 +144,33 @@ func NewClient(cfg *config.Config) *Client {
 // Create creates a new transfer quote.
 func (c *QuotesClient) Create(ctx context.Context, params *CreateQuoteParams) (*CreateQuoteResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/transfer-quotes", c.instanceID)
-	return request.Do[*CreateQuoteResponse](c.cfg, ctx, "POST", path, params)
+	return request.Do[*CreateQuoteResponse](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("transfers.quotes.Create", "/instances/{instance_id}/transfer-quotes"))
 }
 
 // Create creates a new transfer.
 func (c *Client) Create(ctx context.Context, params *CreateParams) (*CreateResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/transfers", c.instanceID)
-	return request.Do[*CreateResponse](c.cfg, ctx, "POST", path, params)
+	var key string
+	resp, err := request.Do[*CreateResponse](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("transfers.Create", "/instances/{instance_id}/transfers"),
+		request.WithIdempotency(params.IdempotencyKey, &key))
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		resp.IdempotencyKey = key
+	}
+
+	return resp, nil
 }
 
 // List retrieves all transfers with optional pagination.
@@ -175,25 +190,48 @@ func (c *Client) List(ctx context.Context, params *ListParams) (*ListResponse, e
 		}
 	}
 
-	return request.Do[*ListResponse](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*ListResponse](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("transfers.List", "/instances/{instance_id}/transfers"),
+		request.WithParams(params))
+}
+
+// ListAll returns an iterator over all the transfers matching params. It
+// fetches pages lazily, starting at params.Offset, and params.Limit serves as
+// the page size hint.
+func (c *Client) ListAll(ctx context.Context, params *ListParams) *pagination.Iterator[Transfer] {
+	page := ListParams{}
+	if params != nil {
+		page = *params
+	}
+
+	return pagination.New(ctx, func(ctx context.Context) ([]Transfer, bool, error) {
+		resp, err := c.List(ctx, &page)
+		if err != nil {
+			return nil, false, err
+		}
+		page.Offset += len(resp.Data)
+		return resp.Data, resp.Pagination.HasMore, nil
+	})
 }
 
 // Get retrieves a specific transfer by ID.
 func (c *Client) Get(ctx context.Context, transferID string) (*Transfer, error) {
 	if transferID == "" {
-		return nil, fmt.Errorf("transfer ID cannot be empty")
+		return nil, request.EmptyParam("transfer ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/transfers/%s", c.instanceID, transferID)
-	return request.Do[*Transfer](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*Transfer](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("transfers.Get", "/instances/{instance_id}/transfers/{transfer_id}"))
 }
 
 // GetTrack retrieves tracking information for a transfer (public endpoint).
 func (c *Client) GetTrack(ctx context.Context, transferID string) (*Transfer, error) {
 	if transferID == "" {
-		return nil, fmt.Errorf("transfer ID cannot be empty")
+		return nil, request.EmptyParam("transfer ID")
 	}
 
 	path := fmt.Sprintf("/e/transfers/%s", transferID)
-	return request.Do[*Transfer](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*Transfer](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("transfers.GetTrack", "/e/transfers/{transfer_id}"))
 }
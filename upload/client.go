@@ -3,13 +3,12 @@ package upload
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
-	"net/http"
 
 	"github.com/blindpaylabs/blindpay-go/internal/config"
+	"github.com/blindpaylabs/blindpay-go/internal/request"
 )
 
 // Bucket represents the upload destination bucket.
@@ -36,31 +35,25 @@ type UploadResponse struct {
 
 // Client handles file upload operations.
 type Client struct {
-	baseURL    string
-	apiKey     string
+	cfg        *request.Config
 	instanceID string
-	httpClient *http.Client
-	userAgent  string
 }
 
 // NewClient creates a new upload client.
 func NewClient(cfg *config.Config) *Client {
 	return &Client{
-		baseURL:    cfg.BaseURL,
-		apiKey:     cfg.APIKey,
+		cfg:        cfg.ToRequestConfig(),
 		instanceID: cfg.InstanceID,
-		httpClient: cfg.HTTPClient,
-		userAgent:  cfg.UserAgent,
 	}
 }
 
 // Upload uploads a file.
 func (c *Client) Upload(ctx context.Context, params *UploadParams) (*UploadResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.File == nil {
-		return nil, fmt.Errorf("file cannot be nil")
+		return nil, request.NilParam("file")
 	}
 
 	var body bytes.Buffer
@@ -87,36 +80,14 @@ func (c *Client) Upload(ctx context.Context, params *UploadParams) (*UploadRespo
 		instanceID = c.instanceID
 	}
 
-	url := fmt.Sprintf("%s/upload?instance_id=%s", c.baseURL, instanceID)
+	path := fmt.Sprintf("/upload?instance_id=%s", instanceID)
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, &body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	req.Header.Set("User-Agent", c.userAgent)
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("upload failed with status %d: %s", resp.StatusCode, string(respBody))
-	}
-
-	var result UploadResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	raw := &request.RawBody{
+		ContentType: writer.FormDataContentType(),
+		Data:        body.Bytes(),
 	}
 
-	return &result, nil
+	return request.Do[*UploadResponse](c.cfg, ctx, "POST", path, raw,
+		request.WithOperation("upload.Upload", "/upload"),
+		request.WithParams(params))
 }
@@ -0,0 +1,54 @@
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/blindpaylabs/blindpay-go/internal/blindpaytest"
+	"github.com/blindpaylabs/blindpay-go/internal/config"
+	"github.com/blindpaylabs/blindpay-go/internal/request"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpload_Upload(t *testing.T) {
+	rt := &blindpaytest.RoundTripper{
+		T:      t,
+		Out:    json.RawMessage(`{"file_url":"https://example.com/file.png"}`),
+		Method: http.MethodPost,
+		Path:   "/upload",
+	}
+
+	var operation string
+	cfg := &config.Config{
+		BaseURL:    "https://api.blindpay.com",
+		APIKey:     "test_key",
+		InstanceID: "in_000000000000",
+		HTTPClient: &http.Client{Transport: rt},
+		UserAgent:  "test",
+		Middleware: []request.Middleware{func(next request.Handler) request.Handler {
+			return func(ctx context.Context, req *request.Request) (*request.Response, error) {
+				operation = req.Operation
+				return next(ctx, req)
+			}
+		}},
+	}
+
+	client := NewClient(cfg)
+	resp, err := client.Upload(context.Background(), &UploadParams{
+		File:     strings.NewReader("hello"),
+		FileName: "hello.txt",
+		Bucket:   BucketOnboarding,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/file.png", resp.FileURL)
+	require.Equal(t, "upload.Upload", operation)
+
+	req := rt.Requests[0]
+	require.Equal(t, "in_000000000000", req.URL.Query().Get("instance_id"))
+	require.True(t, strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/form-data"))
+	require.NoError(t, req.ParseMultipartForm(1<<20))
+	require.Equal(t, "onboarding", req.FormValue("bucket"))
+}
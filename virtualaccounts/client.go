@@ -89,10 +89,10 @@ func NewClient(cfg *config.Config) *Client {
 // Create creates a new virtual account.
 func (c *Client) Create(ctx context.Context, params *CreateParams) (*VirtualAccount, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/virtual-accounts", c.instanceID, params.ReceiverID)
@@ -111,42 +111,46 @@ func (c *Client) Create(ctx context.Context, params *CreateParams) (*VirtualAcco
 		SoleProprietorDocFile: params.SoleProprietorDocFile,
 	}
 
-	return request.Do[*VirtualAccount](c.cfg, ctx, "POST", path, body)
+	return request.Do[*VirtualAccount](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("virtualaccounts.Create", "/instances/{instance_id}/receivers/{receiver_id}/virtual-accounts"),
+		request.WithParams(params))
 }
 
 // Get retrieves a virtual account by ID.
 func (c *Client) Get(ctx context.Context, receiverID, virtualAccountID string) (*VirtualAccount, error) {
 	if receiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 	if virtualAccountID == "" {
-		return nil, fmt.Errorf("virtual account ID cannot be empty")
+		return nil, request.EmptyParam("virtual account ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/virtual-accounts/%s", c.instanceID, receiverID, virtualAccountID)
-	return request.Do[*VirtualAccount](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*VirtualAccount](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("virtualaccounts.Get", "/instances/{instance_id}/receivers/{receiver_id}/virtual-accounts/{virtual_account_id}"))
 }
 
 // List retrieves all virtual accounts for a receiver.
 func (c *Client) List(ctx context.Context, receiverID string) ([]VirtualAccount, error) {
 	if receiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/virtual-accounts", c.instanceID, receiverID)
-	return request.Do[[]VirtualAccount](c.cfg, ctx, "GET", path, nil)
+	return request.Do[[]VirtualAccount](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("virtualaccounts.List", "/instances/{instance_id}/receivers/{receiver_id}/virtual-accounts"))
 }
 
 // Update updates a virtual account.
 func (c *Client) Update(ctx context.Context, params *UpdateParams) error {
 	if params == nil {
-		return fmt.Errorf("params cannot be nil")
+		return request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return fmt.Errorf("receiver ID cannot be empty")
+		return request.EmptyParam("receiver ID")
 	}
 	if params.VirtualAccountID == "" {
-		return fmt.Errorf("virtual account ID cannot be empty")
+		return request.EmptyParam("virtual account ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/virtual-accounts/%s", c.instanceID, params.ReceiverID, params.VirtualAccountID)
@@ -159,6 +163,8 @@ func (c *Client) Update(ctx context.Context, params *UpdateParams) error {
 		Token:              params.Token,
 	}
 
-	_, err := request.Do[struct{}](c.cfg, ctx, "PUT", path, body)
+	_, err := request.Do[struct{}](c.cfg, ctx, "PUT", path, body,
+		request.WithOperation("virtualaccounts.Update", "/instances/{instance_id}/receivers/{receiver_id}/virtual-accounts/{virtual_account_id}"),
+		request.WithParams(params))
 	return err
 }
@@ -97,20 +97,21 @@ func NewClient(cfg *config.Config) *Client {
 // List retrieves all blockchain wallets for a receiver.
 func (c *Client) List(ctx context.Context, receiverID string) ([]BlockchainWallet, error) {
 	if receiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/blockchain-wallets", c.instanceID, receiverID)
-	return request.Do[[]BlockchainWallet](c.cfg, ctx, "GET", path, nil)
+	return request.Do[[]BlockchainWallet](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("wallets.List", "/instances/{instance_id}/receivers/{receiver_id}/blockchain-wallets"))
 }
 
 // CreateWithAddress creates a new blockchain wallet with an address.
 func (c *Client) CreateWithAddress(ctx context.Context, params *CreateWithAddressParams) (*BlockchainWallet, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/blockchain-wallets", c.instanceID, params.ReceiverID)
@@ -127,16 +128,18 @@ func (c *Client) CreateWithAddress(ctx context.Context, params *CreateWithAddres
 		IsAccountAbstraction: true,
 	}
 
-	return request.Do[*BlockchainWallet](c.cfg, ctx, "POST", path, body)
+	return request.Do[*BlockchainWallet](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("wallets.CreateWithAddress", "/instances/{instance_id}/receivers/{receiver_id}/blockchain-wallets"),
+		request.WithParams(params))
 }
 
 // CreateWithHash creates a new blockchain wallet with a signature transaction hash.
 func (c *Client) CreateWithHash(ctx context.Context, params *CreateWithHashParams) (*BlockchainWallet, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/blockchain-wallets", c.instanceID, params.ReceiverID)
@@ -153,50 +156,55 @@ func (c *Client) CreateWithHash(ctx context.Context, params *CreateWithHashParam
 		IsAccountAbstraction: false,
 	}
 
-	return request.Do[*BlockchainWallet](c.cfg, ctx, "POST", path, body)
+	return request.Do[*BlockchainWallet](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("wallets.CreateWithHash", "/instances/{instance_id}/receivers/{receiver_id}/blockchain-wallets"),
+		request.WithParams(params))
 }
 
 // GetWalletMessage retrieves the wallet message for signing.
 func (c *Client) GetWalletMessage(ctx context.Context, receiverID string) (*GetMessageResponse, error) {
 	if receiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/blockchain-wallets/sign-message", c.instanceID, receiverID)
-	return request.Do[*GetMessageResponse](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*GetMessageResponse](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("wallets.GetWalletMessage", "/instances/{instance_id}/receivers/{receiver_id}/blockchain-wallets/sign-message"))
 }
 
 // Get retrieves a specific blockchain wallet.
 func (c *Client) Get(ctx context.Context, receiverID, id string) (*BlockchainWallet, error) {
 	if receiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 	if id == "" {
-		return nil, fmt.Errorf("id cannot be empty")
+		return nil, request.EmptyParam("id")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/blockchain-wallets/%s", c.instanceID, receiverID, id)
-	return request.Do[*BlockchainWallet](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*BlockchainWallet](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("wallets.Get", "/instances/{instance_id}/receivers/{receiver_id}/blockchain-wallets/{id}"))
 }
 
 // Delete deletes a blockchain wallet.
 func (c *Client) Delete(ctx context.Context, receiverID, id string) error {
 	if receiverID == "" {
-		return fmt.Errorf("receiver ID cannot be empty")
+		return request.EmptyParam("receiver ID")
 	}
 	if id == "" {
-		return fmt.Errorf("id cannot be empty")
+		return request.EmptyParam("id")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/blockchain-wallets/%s", c.instanceID, receiverID, id)
-	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil)
+	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil,
+		request.WithOperation("wallets.Delete", "/instances/{instance_id}/receivers/{receiver_id}/blockchain-wallets/{id}"))
 	return err
 }
 
 // CreateAssetTrustline creates an asset trustline on Stellar.
 func (c *Client) CreateAssetTrustline(ctx context.Context, address string) (*CreateAssetTrustlineResponse, error) {
 	if address == "" {
-		return nil, fmt.Errorf("address cannot be empty")
+		return nil, request.EmptyParam("address")
 	}
 
 	path := fmt.Sprintf("/instances/%s/create-asset-trustline", c.instanceID)
@@ -206,38 +214,42 @@ func (c *Client) CreateAssetTrustline(ctx context.Context, address string) (*Cre
 		Address: address,
 	}
 
-	return request.Do[*CreateAssetTrustlineResponse](c.cfg, ctx, "POST", path, body)
+	return request.Do[*CreateAssetTrustlineResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("wallets.CreateAssetTrustline", "/instances/{instance_id}/create-asset-trustline"))
 }
 
 // MintUsdbStellar mints USDB on Stellar.
 func (c *Client) MintUsdbStellar(ctx context.Context, params *MintUsdbStellarParams) error {
 	if params == nil {
-		return fmt.Errorf("params cannot be nil")
+		return request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/mint-usdb-stellar", c.instanceID)
-	_, err := request.Do[struct{}](c.cfg, ctx, "POST", path, params)
+	_, err := request.Do[struct{}](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("wallets.MintUsdbStellar", "/instances/{instance_id}/mint-usdb-stellar"))
 	return err
 }
 
 // MintUsdbSolana mints USDB on Solana.
 func (c *Client) MintUsdbSolana(ctx context.Context, params *MintUsdbSolanaParams) (*MintUsdbSolanaResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/mint-usdb-solana", c.instanceID)
-	return request.Do[*MintUsdbSolanaResponse](c.cfg, ctx, "POST", path, params)
+	return request.Do[*MintUsdbSolanaResponse](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("wallets.MintUsdbSolana", "/instances/{instance_id}/mint-usdb-solana"))
 }
 
 // PrepareSolanaDelegationTransaction prepares a Solana delegation transaction.
 func (c *Client) PrepareSolanaDelegationTransaction(ctx context.Context, params *PrepareSolanaDelegationTransactionParams) (*PrepareSolanaDelegationTransactionResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/prepare-delegate-solana", c.instanceID)
-	return request.Do[*PrepareSolanaDelegationTransactionResponse](c.cfg, ctx, "POST", path, params)
+	return request.Do[*PrepareSolanaDelegationTransactionResponse](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("wallets.PrepareSolanaDelegationTransaction", "/instances/{instance_id}/prepare-delegate-solana"))
 }
 
 // OfframpWallet represents an offramp wallet.
@@ -286,27 +298,28 @@ func NewOfframpClient(cfg *config.Config) *OfframpClient {
 // List retrieves all offramp wallets for a bank account.
 func (c *OfframpClient) List(ctx context.Context, receiverID, bankAccountID string) ([]OfframpWallet, error) {
 	if receiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 	if bankAccountID == "" {
-		return nil, fmt.Errorf("bank account ID cannot be empty")
+		return nil, request.EmptyParam("bank account ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/bank-accounts/%s/offramp-wallets",
 		c.instanceID, receiverID, bankAccountID)
-	return request.Do[[]OfframpWallet](c.cfg, ctx, "GET", path, nil)
+	return request.Do[[]OfframpWallet](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("wallets.offramp.List", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts/{bank_account_id}/offramp-wallets"))
 }
 
 // Create creates a new offramp wallet.
 func (c *OfframpClient) Create(ctx context.Context, params *CreateOfframpWalletParams) (*CreateOfframpWalletResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 	if params.ReceiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 	if params.BankAccountID == "" {
-		return nil, fmt.Errorf("bank account ID cannot be empty")
+		return nil, request.EmptyParam("bank account ID")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/bank-accounts/%s/offramp-wallets",
@@ -320,22 +333,25 @@ func (c *OfframpClient) Create(ctx context.Context, params *CreateOfframpWalletP
 		Network:    params.Network,
 	}
 
-	return request.Do[*CreateOfframpWalletResponse](c.cfg, ctx, "POST", path, body)
+	return request.Do[*CreateOfframpWalletResponse](c.cfg, ctx, "POST", path, body,
+		request.WithOperation("wallets.offramp.Create", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts/{bank_account_id}/offramp-wallets"),
+		request.WithParams(params))
 }
 
 // Get retrieves a specific offramp wallet.
 func (c *OfframpClient) Get(ctx context.Context, receiverID, bankAccountID, id string) (*OfframpWallet, error) {
 	if receiverID == "" {
-		return nil, fmt.Errorf("receiver ID cannot be empty")
+		return nil, request.EmptyParam("receiver ID")
 	}
 	if bankAccountID == "" {
-		return nil, fmt.Errorf("bank account ID cannot be empty")
+		return nil, request.EmptyParam("bank account ID")
 	}
 	if id == "" {
-		return nil, fmt.Errorf("id cannot be empty")
+		return nil, request.EmptyParam("id")
 	}
 
 	path := fmt.Sprintf("/instances/%s/receivers/%s/bank-accounts/%s/offramp-wallets/%s",
 		c.instanceID, receiverID, bankAccountID, id)
-	return request.Do[*OfframpWallet](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*OfframpWallet](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("wallets.offramp.Get", "/instances/{instance_id}/receivers/{receiver_id}/bank-accounts/{bank_account_id}/offramp-wallets/{id}"))
 }
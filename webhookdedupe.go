@@ -0,0 +1,329 @@
+package blindpay
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// DedupeStatus is the outcome of claiming a webhook delivery in a DedupeStore.
+type DedupeStatus int
+
+const (
+	// DedupeClaimed means the delivery was not seen before, and the caller now
+	// owns its processing.
+	DedupeClaimed DedupeStatus = iota
+	// DedupeInProgress means another attempt of the delivery is still being
+	// processed.
+	DedupeInProgress
+	// DedupeDuplicate means the delivery was already processed.
+	DedupeDuplicate
+)
+
+// DedupeToken identifies a claim of a delivery. Claim returns it with
+// DedupeClaimed, and Complete and Release only act on the delivery while that
+// claim is still its current one.
+type DedupeToken int64
+
+// DefaultDedupeLease is how long a claim on a delivery lasts before a store
+// hands the delivery to another attempt, in case the process that claimed it
+// died before completing or releasing it.
+const DefaultDedupeLease = 5 * time.Minute
+
+// DedupeStore records the webhook-id of the deliveries a WebhookHandler
+// processes, so that Svix redeliveries are acknowledged without being
+// processed twice.
+//
+// Implementations must be safe for concurrent use, and Claim must be atomic:
+// of two concurrent claims of the same id, only one yields DedupeClaimed.
+//
+// Complete and Release are given the token of the claim they end. Unless that
+// claim is still the current one, they change nothing and return
+// ErrWebhookClaimLost, so that a slow attempt whose lease expired cannot
+// release or complete the claim of the attempt that took the delivery over.
+type DedupeStore interface {
+	// Claim marks the delivery id as being processed. With DedupeClaimed,
+	// it returns the token of the new claim.
+	Claim(ctx context.Context, id string) (DedupeStatus, DedupeToken, error)
+	// Complete marks the claimed delivery id as processed.
+	Complete(ctx context.Context, id string, token DedupeToken) error
+	// Release forgets the claimed delivery id, so that its next attempt is
+	// processed.
+	Release(ctx context.Context, id string, token DedupeToken) error
+}
+
+// MemoryDedupeStore is a DedupeStore keeping the most recently claimed
+// deliveries in memory. It suits a single process; deliveries evicted from it
+// or claimed by another process are processed again.
+type MemoryDedupeStore struct {
+	capacity int
+	lease    time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+	claims  DedupeToken
+}
+
+type dedupeEntry struct {
+	id        string
+	done      bool
+	claimedAt time.Time
+	token     DedupeToken
+}
+
+// NewMemoryDedupeStore returns a store remembering up to capacity deliveries,
+// evicting the least recently claimed first. A lease of zero uses
+// DefaultDedupeLease.
+func NewMemoryDedupeStore(capacity int, lease time.Duration) *MemoryDedupeStore {
+	if capacity <= 0 {
+		capacity = 10000
+	}
+	if lease <= 0 {
+		lease = DefaultDedupeLease
+	}
+	return &MemoryDedupeStore{
+		capacity: capacity,
+		lease:    lease,
+		now:      time.Now,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Claim implements DedupeStore. Its tokens count the claims made on the
+// store.
+func (s *MemoryDedupeStore) Claim(_ context.Context, id string) (DedupeStatus, DedupeToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if elem, ok := s.entries[id]; ok {
+		s.order.MoveToFront(elem)
+		entry := elem.Value.(*dedupeEntry)
+		switch {
+		case entry.done:
+			return DedupeDuplicate, 0, nil
+		case now.Sub(entry.claimedAt) < s.lease:
+			return DedupeInProgress, 0, nil
+		}
+		s.claims++
+		entry.claimedAt = now
+		entry.token = s.claims
+		return DedupeClaimed, entry.token, nil
+	}
+
+	s.claims++
+	s.entries[id] = s.order.PushFront(&dedupeEntry{id: id, claimedAt: now, token: s.claims})
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*dedupeEntry).id)
+	}
+	return DedupeClaimed, s.claims, nil
+}
+
+// Complete implements DedupeStore.
+func (s *MemoryDedupeStore) Complete(_ context.Context, id string, token DedupeToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, err := s.claimed(id, token)
+	if err != nil {
+		return err
+	}
+	elem.Value.(*dedupeEntry).done = true
+	return nil
+}
+
+// Release implements DedupeStore.
+func (s *MemoryDedupeStore) Release(_ context.Context, id string, token DedupeToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, err := s.claimed(id, token)
+	if err != nil {
+		return err
+	}
+	s.order.Remove(elem)
+	delete(s.entries, id)
+	return nil
+}
+
+// claimed returns the entry of the delivery id, or ErrWebhookClaimLost unless
+// it is in progress under the claim token. s.mu must be held.
+func (s *MemoryDedupeStore) claimed(id string, token DedupeToken) (*list.Element, error) {
+	elem, ok := s.entries[id]
+	if !ok {
+		return nil, ErrWebhookClaimLost
+	}
+	if entry := elem.Value.(*dedupeEntry); entry.done || entry.token != token {
+		return nil, ErrWebhookClaimLost
+	}
+	return elem, nil
+}
+
+// SQLPlaceholder is the bind parameter syntax of a database/sql driver.
+type SQLPlaceholder int
+
+const (
+	// SQLPlaceholderQuestion is the "?" syntax of MySQL and SQLite drivers.
+	SQLPlaceholderQuestion SQLPlaceholder = iota
+	// SQLPlaceholderDollar is the "$1" syntax of PostgreSQL drivers.
+	SQLPlaceholderDollar
+)
+
+// bind returns the query with its "?" parameters rewritten to p.
+func (p SQLPlaceholder) bind(query string) string {
+	if p != SQLPlaceholderDollar {
+		return query
+	}
+	out := make([]byte, 0, len(query)+8)
+	n := 0
+	for i := 0; i < len(query); i++ {
+		if query[i] != '?' {
+			out = append(out, query[i])
+			continue
+		}
+		n++
+		out = append(out, fmt.Sprintf("$%d", n)...)
+	}
+	return string(out)
+}
+
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+const (
+	dedupeProcessing = "processing"
+	dedupeDone       = "done"
+)
+
+// SQLDedupeStore is a DedupeStore backed by a database/sql table, shared by
+// every process receiving the webhooks. The table is created by CreateTable
+// and holds one row per delivery:
+//
+//	id         VARCHAR(255) PRIMARY KEY
+//	status     VARCHAR(16)  "processing" or "done"
+//	updated_at BIGINT       Unix milliseconds of the last claim or completion
+type SQLDedupeStore struct {
+	db          *sql.DB
+	table       string
+	placeholder SQLPlaceholder
+	lease       time.Duration
+	now         func() time.Time
+}
+
+// NewSQLDedupeStore returns a store using table in db, binding parameters with
+// placeholder. A lease of zero uses DefaultDedupeLease.
+func NewSQLDedupeStore(db *sql.DB, table string, placeholder SQLPlaceholder, lease time.Duration) (*SQLDedupeStore, error) {
+	if db == nil {
+		return nil, errors.New("blindpay: nil database")
+	}
+	if !sqlIdentifier.MatchString(table) {
+		return nil, fmt.Errorf("blindpay: invalid table name %q", table)
+	}
+	if lease <= 0 {
+		lease = DefaultDedupeLease
+	}
+	return &SQLDedupeStore{
+		db:          db,
+		table:       table,
+		placeholder: placeholder,
+		lease:       lease,
+		now:         time.Now,
+	}, nil
+}
+
+// CreateTable creates the table of the store if it does not exist.
+func (s *SQLDedupeStore) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s (id VARCHAR(255) PRIMARY KEY, status VARCHAR(16) NOT NULL, updated_at BIGINT NOT NULL)",
+		s.table))
+	return err
+}
+
+// Claim implements DedupeStore. It inserts the delivery, and falls back to
+// reading the existing row when the insert fails, so that it works with any
+// driver regardless of how it reports primary key violations. Its tokens are
+// the updated_at of the claims.
+func (s *SQLDedupeStore) Claim(ctx context.Context, id string) (DedupeStatus, DedupeToken, error) {
+	now := s.now().UnixMilli()
+
+	_, insertErr := s.db.ExecContext(ctx, s.placeholder.bind(fmt.Sprintf(
+		"INSERT INTO %s (id, status, updated_at) VALUES (?, ?, ?)", s.table)),
+		id, dedupeProcessing, now)
+	if insertErr == nil {
+		return DedupeClaimed, DedupeToken(now), nil
+	}
+
+	var status string
+	var updatedAt int64
+	err := s.db.QueryRowContext(ctx, s.placeholder.bind(fmt.Sprintf(
+		"SELECT status, updated_at FROM %s WHERE id = ?", s.table)), id).Scan(&status, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, fmt.Errorf("failed to claim webhook %s: %w", id, insertErr)
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to claim webhook %s: %w", id, err)
+	}
+
+	if status == dedupeDone {
+		return DedupeDuplicate, 0, nil
+	}
+	if now-updatedAt < s.lease.Milliseconds() {
+		return DedupeInProgress, 0, nil
+	}
+
+	// The claim expired. Take it over unless another attempt just did.
+	res, err := s.db.ExecContext(ctx, s.placeholder.bind(fmt.Sprintf(
+		"UPDATE %s SET updated_at = ? WHERE id = ? AND status = ? AND updated_at = ?", s.table)),
+		now, id, dedupeProcessing, updatedAt)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to claim webhook %s: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		return DedupeInProgress, 0, nil
+	}
+	return DedupeClaimed, DedupeToken(now), nil
+}
+
+// Complete implements DedupeStore.
+func (s *SQLDedupeStore) Complete(ctx context.Context, id string, token DedupeToken) error {
+	res, err := s.db.ExecContext(ctx, s.placeholder.bind(fmt.Sprintf(
+		"UPDATE %s SET status = ?, updated_at = ? WHERE id = ? AND status = ? AND updated_at = ?", s.table)),
+		dedupeDone, s.now().UnixMilli(), id, dedupeProcessing, int64(token))
+	if err != nil {
+		return fmt.Errorf("failed to complete webhook %s: %w", id, err)
+	}
+	return claimHeld(res)
+}
+
+// Release implements DedupeStore.
+func (s *SQLDedupeStore) Release(ctx context.Context, id string, token DedupeToken) error {
+	res, err := s.db.ExecContext(ctx, s.placeholder.bind(fmt.Sprintf(
+		"DELETE FROM %s WHERE id = ? AND status = ? AND updated_at = ?", s.table)),
+		id, dedupeProcessing, int64(token))
+	if err != nil {
+		return fmt.Errorf("failed to release webhook %s: %w", id, err)
+	}
+	return claimHeld(res)
+}
+
+// Prune deletes the deliveries completed before the given time, and returns
+// how many were deleted. Svix stops retrying a delivery after a few days, so
+// rows older than that are no longer needed.
+func (s *SQLDedupeStore) Prune(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, s.placeholder.bind(fmt.Sprintf(
+		"DELETE FROM %s WHERE status = ? AND updated_at < ?", s.table)),
+		dedupeDone, before.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune webhooks: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,204 @@
+package blindpay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blindpaylabs/blindpay-go/payouts"
+)
+
+func TestDedupeStores(t *testing.T) {
+	ctx := context.Background()
+
+	stores := map[string]func(t *testing.T, now func() time.Time) DedupeStore{
+		"memory": func(t *testing.T, now func() time.Time) DedupeStore {
+			store := NewMemoryDedupeStore(10, time.Minute)
+			store.now = now
+			return store
+		},
+		"sql": func(t *testing.T, now func() time.Time) DedupeStore {
+			store, err := NewSQLDedupeStore(openSQLDB(t), "webhooks", SQLPlaceholderQuestion, time.Minute)
+			require.NoError(t, err)
+			require.NoError(t, store.CreateTable(ctx))
+			store.now = now
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			clock := time.Unix(1700000000, 0)
+			store := newStore(t, func() time.Time { return clock })
+
+			status, token, err := store.Claim(ctx, "msg_1")
+			require.NoError(t, err)
+			require.Equal(t, DedupeClaimed, status)
+
+			status, _, err = store.Claim(ctx, "msg_1")
+			require.NoError(t, err)
+			require.Equal(t, DedupeInProgress, status)
+
+			require.NoError(t, store.Complete(ctx, "msg_1", token))
+			require.ErrorIs(t, store.Complete(ctx, "msg_1", token), ErrWebhookClaimLost)
+			require.ErrorIs(t, store.Release(ctx, "msg_1", token), ErrWebhookClaimLost)
+			status, _, err = store.Claim(ctx, "msg_1")
+			require.NoError(t, err)
+			require.Equal(t, DedupeDuplicate, status)
+
+			// A released delivery is processed again.
+			_, token, err = store.Claim(ctx, "msg_2")
+			require.NoError(t, err)
+			require.NoError(t, store.Release(ctx, "msg_2", token))
+			status, token, err = store.Claim(ctx, "msg_2")
+			require.NoError(t, err)
+			require.Equal(t, DedupeClaimed, status)
+
+			// An abandoned claim is taken over once its lease expires, after
+			// which the attempt that held it can no longer end it.
+			clock = clock.Add(2 * time.Minute)
+			status, takeover, err := store.Claim(ctx, "msg_2")
+			require.NoError(t, err)
+			require.Equal(t, DedupeClaimed, status)
+			require.ErrorIs(t, store.Release(ctx, "msg_2", token), ErrWebhookClaimLost)
+			require.ErrorIs(t, store.Complete(ctx, "msg_2", token), ErrWebhookClaimLost)
+			status, _, err = store.Claim(ctx, "msg_2")
+			require.NoError(t, err)
+			require.Equal(t, DedupeInProgress, status)
+			require.NoError(t, store.Complete(ctx, "msg_2", takeover))
+
+			status, _, err = store.Claim(ctx, "msg_1")
+			require.NoError(t, err)
+			require.Equal(t, DedupeDuplicate, status)
+		})
+	}
+}
+
+func TestMemoryDedupeStore_Evicts(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryDedupeStore(2, 0)
+
+	for _, id := range []string{"msg_1", "msg_2", "msg_3"} {
+		_, token, err := store.Claim(ctx, id)
+		require.NoError(t, err)
+		require.NoError(t, store.Complete(ctx, id, token))
+	}
+
+	status, _, err := store.Claim(ctx, "msg_3")
+	require.NoError(t, err)
+	require.Equal(t, DedupeDuplicate, status)
+	status, _, err = store.Claim(ctx, "msg_1")
+	require.NoError(t, err)
+	require.Equal(t, DedupeClaimed, status)
+}
+
+func TestSQLDedupeStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid table", func(t *testing.T) {
+		_, err := NewSQLDedupeStore(openSQLDB(t), "webhooks; DROP TABLE users", SQLPlaceholderQuestion, 0)
+		require.Error(t, err)
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		store, err := NewSQLDedupeStore(openSQLDB(t), "webhooks", SQLPlaceholderQuestion, 0)
+		require.NoError(t, err)
+
+		var tokens []DedupeToken
+		for _, id := range []string{"msg_1", "msg_2"} {
+			_, token, err := store.Claim(ctx, id)
+			require.NoError(t, err)
+			tokens = append(tokens, token)
+		}
+		require.NoError(t, store.Complete(ctx, "msg_1", tokens[0]))
+
+		n, err := store.Prune(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+	})
+
+	t.Run("dollar placeholders", func(t *testing.T) {
+		require.Equal(t,
+			"UPDATE t SET updated_at = $1 WHERE id = $2",
+			SQLPlaceholderDollar.bind("UPDATE t SET updated_at = ? WHERE id = ?"))
+	})
+}
+
+func TestWebhookHandler_Dedupe(t *testing.T) {
+	secret := "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	payload := `{"webhook_event":"payout.complete","data":{"id":"pa_000000000000","status":"completed"}}`
+
+	t.Run("processes redeliveries once", func(t *testing.T) {
+		calls := 0
+		handler := NewWebhookHandler(secret,
+			WithWebhookDedupe(NewMemoryDedupeStore(0, 0)),
+			OnPayoutComplete(func(ctx context.Context, payout *payouts.Payout) error {
+				calls++
+				return nil
+			}),
+		)
+
+		require.Equal(t, http.StatusNoContent, deliver(t, handler, secret, payload).Code)
+		require.Equal(t, http.StatusNoContent, deliver(t, handler, secret, payload).Code)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("retries failed deliveries", func(t *testing.T) {
+		calls := 0
+		handler := NewWebhookHandler(secret,
+			WithWebhookDedupe(NewMemoryDedupeStore(0, 0)),
+			OnPayoutComplete(func(ctx context.Context, payout *payouts.Payout) error {
+				calls++
+				if calls == 1 {
+					return errors.New("ledger unavailable")
+				}
+				return nil
+			}),
+		)
+
+		require.Equal(t, http.StatusInternalServerError, deliver(t, handler, secret, payload).Code)
+		require.Equal(t, http.StatusNoContent, deliver(t, handler, secret, payload).Code)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("conflicts with deliveries in progress", func(t *testing.T) {
+		store := NewMemoryDedupeStore(0, 0)
+		_, _, err := store.Claim(context.Background(), "msg_000000000000")
+		require.NoError(t, err)
+		handler := NewWebhookHandler(secret, WithWebhookDedupe(store))
+
+		require.Equal(t, http.StatusConflict, deliver(t, handler, secret, payload).Code)
+	})
+}
+
+func TestWebhookHandler_Tolerance(t *testing.T) {
+	secret := "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	payload := `{"webhook_event":"payout.complete","data":{"id":"pa_000000000000"}}`
+
+	// A delivery signed ten minutes ago.
+	header := signedHeader(t, secret, "msg_000000000000", time.Now().Add(-10*time.Minute), payload)
+
+	_, err := ParseWebhookEvent(secret, header, []byte(payload))
+	require.ErrorIs(t, err, ErrInvalidWebhookSignature)
+
+	event, err := parseWebhookEvent([]string{secret}, header, []byte(payload), 15*time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, WebhookEventPayoutComplete, event.Type)
+
+	for tolerance, code := range map[time.Duration]int{
+		time.Minute:      http.StatusUnauthorized,
+		15 * time.Minute: http.StatusNoContent,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(payload))
+		req.Header = header.Clone()
+		rec := httptest.NewRecorder()
+		NewWebhookHandler(secret, WithWebhookTolerance(tolerance)).ServeHTTP(rec, req)
+		require.Equal(t, code, rec.Code, tolerance)
+	}
+}
@@ -59,42 +59,47 @@ func NewClient(cfg *config.Config) *Client {
 // List retrieves all webhook endpoints for the instance.
 func (c *Client) List(ctx context.Context) ([]WebhookEndpoint, error) {
 	path := fmt.Sprintf("/instances/%s/webhook-endpoints", c.instanceID)
-	return request.Do[[]WebhookEndpoint](c.cfg, ctx, "GET", path, nil)
+	return request.Do[[]WebhookEndpoint](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("webhookendpoints.List", "/instances/{instance_id}/webhook-endpoints"))
 }
 
 // Create creates a new webhook endpoint.
 func (c *Client) Create(ctx context.Context, params *CreateParams) (*CreateResponse, error) {
 	if params == nil {
-		return nil, fmt.Errorf("params cannot be nil")
+		return nil, request.NilParam("params")
 	}
 
 	path := fmt.Sprintf("/instances/%s/webhook-endpoints", c.instanceID)
-	return request.Do[*CreateResponse](c.cfg, ctx, "POST", path, params)
+	return request.Do[*CreateResponse](c.cfg, ctx, "POST", path, params,
+		request.WithOperation("webhookendpoints.Create", "/instances/{instance_id}/webhook-endpoints"))
 }
 
 // Delete deletes a webhook endpoint.
 func (c *Client) Delete(ctx context.Context, id string) error {
 	if id == "" {
-		return fmt.Errorf("id cannot be empty")
+		return request.EmptyParam("id")
 	}
 
 	path := fmt.Sprintf("/instances/%s/webhook-endpoints/%s", c.instanceID, id)
-	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil)
+	_, err := request.Do[struct{}](c.cfg, ctx, "DELETE", path, nil,
+		request.WithOperation("webhookendpoints.Delete", "/instances/{instance_id}/webhook-endpoints/{id}"))
 	return err
 }
 
 // GetSecret retrieves the secret for a webhook endpoint.
 func (c *Client) GetSecret(ctx context.Context, id string) (*GetSecretResponse, error) {
 	if id == "" {
-		return nil, fmt.Errorf("id cannot be empty")
+		return nil, request.EmptyParam("id")
 	}
 
 	path := fmt.Sprintf("/instances/%s/webhook-endpoints/%s/secret", c.instanceID, id)
-	return request.Do[*GetSecretResponse](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*GetSecretResponse](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("webhookendpoints.GetSecret", "/instances/{instance_id}/webhook-endpoints/{id}/secret"))
 }
 
 // GetPortalAccessURL retrieves the portal access URL.
 func (c *Client) GetPortalAccessURL(ctx context.Context) (*GetPortalAccessURLResponse, error) {
 	path := fmt.Sprintf("/instances/%s/webhook-endpoints/portal-access", c.instanceID)
-	return request.Do[*GetPortalAccessURLResponse](c.cfg, ctx, "GET", path, nil)
+	return request.Do[*GetPortalAccessURLResponse](c.cfg, ctx, "GET", path, nil,
+		request.WithOperation("webhookendpoints.GetPortalAccessURL", "/instances/{instance_id}/webhook-endpoints/portal-access"))
 }
@@ -0,0 +1,161 @@
+package webhookendpoints
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/blindpaylabs/blindpay-go/internal/request"
+	"github.com/blindpaylabs/blindpay-go/internal/types"
+)
+
+// Plan is the set of changes bringing the webhook endpoints of an instance to
+// a desired set. Endpoints cannot be updated, so an endpoint whose events
+// changed is replaced: it appears in Delete and its new version in Create.
+type Plan struct {
+	// Keep lists the endpoints that already match a desired endpoint.
+	Keep []WebhookEndpoint
+	// Create lists the desired endpoints that do not exist yet.
+	Create []CreateParams
+	// Delete lists the endpoints that match no desired endpoint.
+	Delete []WebhookEndpoint
+	// Created holds the IDs of the endpoints created by ApplyPlan, in the
+	// order of Create. Created endpoints have new signing secrets, to be
+	// retrieved with GetSecret.
+	Created []string
+}
+
+// Empty reports whether the plan changes nothing.
+func (p *Plan) Empty() bool {
+	return len(p.Create) == 0 && len(p.Delete) == 0
+}
+
+// String describes the plan one change per line, as "+ url [events]" for
+// creates and "- url [events] (id)" for deletes, for deploy logs.
+func (p *Plan) String() string {
+	var b strings.Builder
+	for _, params := range p.Create {
+		fmt.Fprintf(&b, "+ %s %v\n", params.URL, params.Events)
+	}
+	for _, endpoint := range p.Delete {
+		fmt.Fprintf(&b, "- %s %v (%s)\n", endpoint.URL, endpoint.Events, endpoint.ID)
+	}
+	return b.String()
+}
+
+// EnsureOption configures EnsureEndpoints.
+type EnsureOption func(*ensureOptions)
+
+type ensureOptions struct {
+	dryRun bool
+}
+
+// DryRun makes EnsureEndpoints return the plan without applying it.
+func DryRun() EnsureOption {
+	return func(o *ensureOptions) {
+		o.dryRun = true
+	}
+}
+
+// EnsureEndpoints makes the webhook endpoints of the instance match desired:
+// it plans the changes with PlanEndpoints and applies them with ApplyPlan,
+// unless the DryRun option is given. It returns the plan in both cases.
+//
+// An endpoint matches a desired one when it has the same URL and subscribes to
+// the same events, in any order. Running EnsureEndpoints again after a failure
+// resumes where it stopped.
+func (c *Client) EnsureEndpoints(ctx context.Context, desired []CreateParams, opts ...EnsureOption) (*Plan, error) {
+	var o ensureOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	plan, err := c.PlanEndpoints(ctx, desired)
+	if err != nil {
+		return nil, err
+	}
+	if o.dryRun {
+		return plan, nil
+	}
+	return plan, c.ApplyPlan(ctx, plan)
+}
+
+// PlanEndpoints compares desired with the endpoints returned by List and
+// returns the changes that make them match, without applying them.
+func (c *Client) PlanEndpoints(ctx context.Context, desired []CreateParams) (*Plan, error) {
+	wanted := make([]CreateParams, len(desired))
+	urls := make(map[string]bool, len(desired))
+	for i, params := range desired {
+		if params.URL == "" {
+			return nil, request.EmptyParam(fmt.Sprintf("desired[%d].URL", i))
+		}
+		if urls[params.URL] {
+			return nil, &request.ParamError{Param: fmt.Sprintf("desired[%d].URL", i), Reason: "is listed more than once"}
+		}
+		urls[params.URL] = true
+		wanted[i] = CreateParams{URL: params.URL, Events: normalizeEvents(params.Events)}
+	}
+
+	existing, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{}
+	matched := make([]bool, len(existing))
+	for _, params := range wanted {
+		found := false
+		for i, endpoint := range existing {
+			if !matched[i] && endpoint.URL == params.URL && slices.Equal(normalizeEvents(endpoint.Events), params.Events) {
+				matched[i] = true
+				plan.Keep = append(plan.Keep, endpoint)
+				found = true
+				break
+			}
+		}
+		if !found {
+			plan.Create = append(plan.Create, params)
+		}
+	}
+	for i, endpoint := range existing {
+		if !matched[i] {
+			plan.Delete = append(plan.Delete, endpoint)
+		}
+	}
+
+	return plan, nil
+}
+
+// ApplyPlan applies plan, recording the IDs of the endpoints it creates in
+// plan.Created. It creates endpoints before deleting any, so that a replaced
+// endpoint keeps receiving events in the meantime, and stops at the first
+// error.
+func (c *Client) ApplyPlan(ctx context.Context, plan *Plan) error {
+	if plan == nil {
+		return request.NilParam("plan")
+	}
+
+	for _, params := range plan.Create {
+		params := params
+		resp, err := c.Create(ctx, &params)
+		if err != nil {
+			return fmt.Errorf("failed to create webhook endpoint %s: %w", params.URL, err)
+		}
+		plan.Created = append(plan.Created, resp.ID)
+	}
+	for _, endpoint := range plan.Delete {
+		if err := c.Delete(ctx, endpoint.ID); err != nil {
+			return fmt.Errorf("failed to delete webhook endpoint %s (%s): %w", endpoint.URL, endpoint.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// normalizeEvents returns events sorted and without duplicates.
+func normalizeEvents(events []types.WebhookEvent) []types.WebhookEvent {
+	normalized := slices.Clone(events)
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
@@ -157,3 +157,97 @@ func TestWebhookEndpoints_GetPortalAccessURL(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, portalURL, response.URL)
 }
+
+func TestWebhookEndpoints_EnsureEndpoints(t *testing.T) {
+	instanceID := "in_000000000000"
+	list := json.RawMessage(`[
+		{"id":"we_000000000001","url":"https://example.com/payouts","events":["payout.update","payout.complete"]},
+		{"id":"we_000000000002","url":"https://example.com/receivers","events":["receiver.new"]},
+		{"id":"we_000000000003","url":"https://example.com/legacy","events":["payin.new"]}
+	]`)
+	desired := []CreateParams{
+		{URL: "https://example.com/payouts", Events: []types.WebhookEvent{types.WebhookEventPayoutComplete, types.WebhookEventPayoutUpdate}},
+		{URL: "https://example.com/receivers", Events: []types.WebhookEvent{types.WebhookEventReceiverNew, types.WebhookEventReceiverUpdate}},
+		{URL: "https://example.com/transfers", Events: []types.WebhookEvent{types.WebhookEventTransferComplete}},
+	}
+
+	newClient := func(rt *blindpaytest.RoundTripper) *Client {
+		return NewClient(&config.Config{
+			BaseURL:    "https://api.blindpay.com",
+			APIKey:     "test-key",
+			InstanceID: instanceID,
+			HTTPClient: &http.Client{Transport: rt},
+			UserAgent:  "test",
+		})
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		rt := &blindpaytest.RoundTripper{T: t, Outs: []json.RawMessage{list}}
+
+		plan, err := newClient(rt).EnsureEndpoints(context.Background(), desired, DryRun())
+		require.NoError(t, err)
+		require.Len(t, rt.Requests, 1)
+		require.Len(t, plan.Keep, 1)
+		require.Equal(t, "we_000000000001", plan.Keep[0].ID)
+		require.Equal(t, []CreateParams{
+			{URL: "https://example.com/receivers", Events: []types.WebhookEvent{types.WebhookEventReceiverNew, types.WebhookEventReceiverUpdate}},
+			{URL: "https://example.com/transfers", Events: []types.WebhookEvent{types.WebhookEventTransferComplete}},
+		}, plan.Create)
+		require.Len(t, plan.Delete, 2)
+		require.Equal(t, "we_000000000002", plan.Delete[0].ID)
+		require.Equal(t, "we_000000000003", plan.Delete[1].ID)
+		require.Empty(t, plan.Created)
+		require.Equal(t, "+ https://example.com/receivers [receiver.new receiver.update]\n"+
+			"+ https://example.com/transfers [transfer.complete]\n"+
+			"- https://example.com/receivers [receiver.new] (we_000000000002)\n"+
+			"- https://example.com/legacy [payin.new] (we_000000000003)\n", plan.String())
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		rt := &blindpaytest.RoundTripper{T: t, Outs: []json.RawMessage{
+			list,
+			json.RawMessage(`{"id":"we_000000000004"}`),
+			json.RawMessage(`{"id":"we_000000000005"}`),
+			json.RawMessage(`{}`),
+			json.RawMessage(`{}`),
+		}}
+
+		plan, err := newClient(rt).EnsureEndpoints(context.Background(), desired)
+		require.NoError(t, err)
+		require.Equal(t, []string{"we_000000000004", "we_000000000005"}, plan.Created)
+
+		var calls []string
+		for _, req := range rt.Requests {
+			calls = append(calls, req.Method+" "+req.URL.Path)
+		}
+		base := "/instances/" + instanceID + "/webhook-endpoints"
+		require.Equal(t, []string{
+			"GET " + base,
+			"POST " + base,
+			"POST " + base,
+			"DELETE " + base + "/we_000000000002",
+			"DELETE " + base + "/we_000000000003",
+		}, calls)
+	})
+
+	t.Run("in sync", func(t *testing.T) {
+		rt := &blindpaytest.RoundTripper{T: t, Outs: []json.RawMessage{list}}
+
+		plan, err := newClient(rt).EnsureEndpoints(context.Background(), []CreateParams{
+			{URL: "https://example.com/payouts", Events: []types.WebhookEvent{types.WebhookEventPayoutUpdate, types.WebhookEventPayoutComplete}},
+			{URL: "https://example.com/receivers", Events: []types.WebhookEvent{types.WebhookEventReceiverNew}},
+			{URL: "https://example.com/legacy", Events: []types.WebhookEvent{types.WebhookEventPayinNew}},
+		})
+		require.NoError(t, err)
+		require.True(t, plan.Empty())
+		require.Len(t, rt.Requests, 1)
+	})
+
+	t.Run("duplicate URL", func(t *testing.T) {
+		rt := &blindpaytest.RoundTripper{T: t}
+
+		_, err := newClient(rt).EnsureEndpoints(context.Background(), []CreateParams{desired[0], desired[0]})
+		require.Error(t, err)
+		require.Empty(t, rt.Requests)
+	})
+}
@@ -0,0 +1,186 @@
+package blindpay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blindpaylabs/blindpay-go/bankaccounts"
+	"github.com/blindpaylabs/blindpay-go/custodialwallets"
+	"github.com/blindpaylabs/blindpay-go/payins"
+	"github.com/blindpaylabs/blindpay-go/payouts"
+	"github.com/blindpaylabs/blindpay-go/receivers"
+	"github.com/blindpaylabs/blindpay-go/transfers"
+	"github.com/blindpaylabs/blindpay-go/virtualaccounts"
+	"github.com/blindpaylabs/blindpay-go/wallets"
+)
+
+// ErrInvalidWebhookSignature is returned by ParseWebhookEvent when the
+// signature headers of a delivery do not match its body.
+var ErrInvalidWebhookSignature = errors.New("blindpay: invalid webhook signature")
+
+// DefaultWebhookTolerance is how far the webhook-timestamp of a delivery may
+// be from now before VerifyWebhook and ParseWebhookEvent reject it as a
+// replay. VerifyWebhookWithTolerance and ParseWebhookEventWithTolerance take
+// another tolerance.
+const DefaultWebhookTolerance = 5 * time.Minute
+
+// WebhookEnvelope is a verified webhook delivery.
+type WebhookEnvelope struct {
+	// Type is the event, such as WebhookEventPayoutUpdate.
+	Type WebhookEvent
+	// ID is the webhook-id of the delivery. It is the same on every retry of
+	// the delivery.
+	ID string
+	// Timestamp is the signed webhook-timestamp of the delivery.
+	Timestamp time.Time
+	// Data holds the payload decoded into the SDK model of the event type:
+	//
+	//	receiver.new, receiver.update               *receivers.Receiver
+	//	bankAccount.new                             *bankaccounts.BankAccount
+	//	payout.*                                    *payouts.Payout
+	//	blockchainWallet.new                        *wallets.BlockchainWallet
+	//	payin.*                                     *payins.Payin
+	//	limitIncrease.new, limitIncrease.update     *receivers.LimitIncreaseRequest
+	//	virtualAccount.new, virtualAccount.complete *virtualaccounts.VirtualAccount
+	//	transfer.*                                  *transfers.Transfer
+	//	wallet.new                                  *custodialwallets.CustodialWallet
+	//
+	// Other event types, such as tos.accept or events added after this
+	// version of the SDK, hold the payload as a json.RawMessage.
+	Data any
+	// Raw is the payload as received.
+	Raw json.RawMessage
+}
+
+// ParseWebhookEvent verifies the Svix signature of a webhook delivery and
+// decodes it into a typed envelope.
+//
+// header holds the headers of the delivery, from which webhook-id,
+// webhook-timestamp and webhook-signature are read, and body is the raw
+// request body. A delivery rejected by VerifyWebhook yields its
+// *WebhookVerificationError, which matches ErrInvalidWebhookSignature unless
+// secret itself is invalid.
+//
+// The event type is read from the "webhook_event" or "type" field of the
+// body. The payload is its "data" field when there is one, and the whole body
+// otherwise.
+func ParseWebhookEvent(secret string, header http.Header, body []byte) (*WebhookEnvelope, error) {
+	return ParseWebhookEventWithTolerance(secret, header, body, DefaultWebhookTolerance)
+}
+
+// ParseWebhookEventWithTolerance is ParseWebhookEvent accepting deliveries
+// whose webhook-timestamp is within tolerance of now. A non-positive tolerance
+// stands for DefaultWebhookTolerance.
+func ParseWebhookEventWithTolerance(secret string, header http.Header, body []byte, tolerance time.Duration) (*WebhookEnvelope, error) {
+	if tolerance <= 0 {
+		tolerance = DefaultWebhookTolerance
+	}
+	return parseWebhookEvent([]string{secret}, header, body, tolerance)
+}
+
+// parseWebhookEvent is ParseWebhookEvent accepting several secrets and a
+// configurable timestamp tolerance.
+func parseWebhookEvent(secrets []string, header http.Header, body []byte, tolerance time.Duration) (*WebhookEnvelope, error) {
+	if err := verifyWebhook(header, body, secrets, tolerance, time.Now()); err != nil {
+		return nil, err
+	}
+
+	id, timestamp, _, _ := webhookHeaders(header)
+	return decodeWebhookEvent(id, timestamp, body)
+}
+
+// decodeWebhookEvent decodes a webhook body whose signature was verified.
+func decodeWebhookEvent(id, timestamp string, body []byte) (*WebhookEnvelope, error) {
+	var fields struct {
+		WebhookEvent WebhookEvent    `json:"webhook_event"`
+		Type         WebhookEvent    `json:"type"`
+		Data         json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook event: %w", err)
+	}
+
+	event := &WebhookEnvelope{
+		Type: fields.WebhookEvent,
+		ID:   id,
+		Raw:  fields.Data,
+	}
+	if event.Type == "" {
+		event.Type = fields.Type
+	}
+	if event.Type == "" {
+		return nil, errors.New("failed to decode webhook event: missing event type")
+	}
+	if len(event.Raw) == 0 || string(event.Raw) == "null" {
+		event.Raw = json.RawMessage(body)
+	}
+
+	if seconds, err := strconv.ParseInt(timestamp, 10, 64); err == nil {
+		event.Timestamp = time.Unix(seconds, 0).UTC()
+	}
+
+	if err := decodeWebhookData(event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// decodeWebhookData sets event.Data from event.Raw.
+func decodeWebhookData(event *WebhookEnvelope) error {
+	data := newWebhookData(event.Type)
+	if data == nil {
+		event.Data = event.Raw
+		return nil
+	}
+	if err := json.Unmarshal(event.Raw, data); err != nil {
+		return fmt.Errorf("failed to decode %s webhook data: %w", event.Type, err)
+	}
+	event.Data = data
+	return nil
+}
+
+// newWebhookData returns a pointer to the zero SDK model of an event type,
+// or nil when the type has no model.
+func newWebhookData(event WebhookEvent) any {
+	switch event {
+	case WebhookEventReceiverNew, WebhookEventReceiverUpdate:
+		return &receivers.Receiver{}
+	case WebhookEventBankAccountNew:
+		return &bankaccounts.BankAccount{}
+	case WebhookEventPayoutNew, WebhookEventPayoutUpdate, WebhookEventPayoutComplete, WebhookEventPayoutPartnerFee:
+		return &payouts.Payout{}
+	case WebhookEventBlockchainWalletNew:
+		return &wallets.BlockchainWallet{}
+	case WebhookEventPayinNew, WebhookEventPayinUpdate, WebhookEventPayinComplete, WebhookEventPayinPartnerFee:
+		return &payins.Payin{}
+	case WebhookEventLimitIncreaseNew, WebhookEventLimitIncreaseUpdate:
+		return &receivers.LimitIncreaseRequest{}
+	case WebhookEventVirtualAccountNew, WebhookEventVirtualAccountComplete:
+		return &virtualaccounts.VirtualAccount{}
+	case WebhookEventTransferNew, WebhookEventTransferUpdate, WebhookEventTransferComplete:
+		return &transfers.Transfer{}
+	case WebhookEventWalletNew:
+		return &custodialwallets.CustodialWallet{}
+	}
+	return nil
+}
+
+// ParseWebhookEvent parses a webhook delivery like the package-level
+// ParseWebhookEvent, and reports the outcome of its signature verification to
+// the client metrics.
+func (c *Client) ParseWebhookEvent(secret string, header http.Header, body []byte) (*WebhookEnvelope, error) {
+	err := VerifyWebhook(header, body, secret)
+	c.observeWebhookVerification(err)
+	if err != nil {
+		return nil, err
+	}
+
+	// A delivery that fails to decode was still signed for real.
+	id, timestamp, _, _ := webhookHeaders(header)
+	return decodeWebhookEvent(id, timestamp, body)
+}
@@ -0,0 +1,143 @@
+package blindpay
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blindpaylabs/blindpay-go/internal/types"
+	"github.com/blindpaylabs/blindpay-go/payouts"
+	"github.com/blindpaylabs/blindpay-go/receivers"
+)
+
+func TestParseWebhookEvent(t *testing.T) {
+	secret := "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+
+	tests := []struct {
+		name    string
+		payload string
+		check   func(t *testing.T, event *WebhookEnvelope)
+	}{
+		{
+			name:    "payout in data field",
+			payload: `{"webhook_event":"payout.update","data":{"id":"pa_000000000000","status":"processing","network":"base"}}`,
+			check: func(t *testing.T, event *WebhookEnvelope) {
+				require.Equal(t, WebhookEventPayoutUpdate, event.Type)
+				payout, ok := event.Data.(*payouts.Payout)
+				require.True(t, ok)
+				require.Equal(t, "pa_000000000000", payout.ID)
+				require.Equal(t, types.TransactionStatusProcessing, payout.Status)
+			},
+		},
+		{
+			name:    "flat limit increase",
+			payload: `{"webhook_event":"limitIncrease.update","id":"rl_000000000000","receiver_id":"re_000000000000","status":"approved"}`,
+			check: func(t *testing.T, event *WebhookEnvelope) {
+				request, ok := event.Data.(*receivers.LimitIncreaseRequest)
+				require.True(t, ok)
+				require.Equal(t, "rl_000000000000", request.ID)
+				require.Equal(t, receivers.LimitIncreaseRequestStatusApproved, request.Status)
+			},
+		},
+		{
+			name:    "unknown event type",
+			payload: `{"type":"instance.update","data":{"id":"in_000000000000"}}`,
+			check: func(t *testing.T, event *WebhookEnvelope) {
+				require.Equal(t, WebhookEvent("instance.update"), event.Type)
+				require.JSONEq(t, `{"id":"in_000000000000"}`, string(event.Data.(json.RawMessage)))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := "msg_p5jXN8AQM9LWM0D4loKWxJek"
+			header := signedHeader(t, secret, id, time.Time{}, tt.payload)
+
+			event, err := ParseWebhookEvent(secret, header, []byte(tt.payload))
+			require.NoError(t, err)
+			require.Equal(t, id, event.ID)
+			require.False(t, event.Timestamp.IsZero())
+			tt.check(t, event)
+		})
+	}
+}
+
+func TestParseWebhookEvent_InvalidSignature(t *testing.T) {
+	secret := "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	payload := `{"webhook_event":"payout.complete","data":{"id":"pa_000000000000"}}`
+	header := signedHeader(t, secret, "msg_p5jXN8AQM9LWM0D4loKWxJek", time.Time{}, payload)
+
+	_, err := ParseWebhookEvent(secret, header, []byte(`{"webhook_event":"payout.complete","data":{"id":"pa_111111111111"}}`))
+	require.True(t, errors.Is(err, ErrInvalidWebhookSignature))
+}
+
+// verificationMetrics records the webhook verification outcomes it receives.
+type verificationMetrics struct {
+	outcomes []string
+}
+
+func (m *verificationMetrics) ObserveRequest(string, string, time.Duration) {}
+func (m *verificationMetrics) ObserveRetry(string, int)                     {}
+func (m *verificationMetrics) ObserveRateLimited(string)                    {}
+
+func (m *verificationMetrics) ObserveWebhookVerification(outcome string) {
+	m.outcomes = append(m.outcomes, outcome)
+}
+
+func TestClient_ParseWebhookEvent_Metrics(t *testing.T) {
+	secret := "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	payload := `{"webhook_event":"payout.complete","data":{"id":"pa_000000000000"}}`
+	expired := signedHeader(t, secret, "", time.Now().Add(-time.Hour), payload)
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  http.Header
+		payload string
+		outcome string
+	}{
+		{name: "valid", secret: secret, header: signedHeader(t, secret, "", time.Time{}, payload), payload: payload, outcome: WebhookVerificationValid},
+		{name: "undecodable", secret: secret, header: signedHeader(t, secret, "", time.Time{}, `{}`), payload: `{}`, outcome: WebhookVerificationValid},
+		{name: "tampered", secret: secret, header: signedHeader(t, secret, "", time.Time{}, payload), payload: `{}`, outcome: WebhookVerificationInvalid},
+		{name: "expired timestamp", secret: secret, header: expired, payload: payload, outcome: WebhookVerificationInvalid},
+		{name: "bad secret", secret: "not a secret", header: signedHeader(t, secret, "", time.Time{}, payload), payload: payload, outcome: WebhookVerificationInvalid},
+		{name: "missing header", secret: secret, header: http.Header{}, payload: payload, outcome: WebhookVerificationInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := &verificationMetrics{}
+			client, err := New("test_key", "in_000000000000", WithMetrics(metrics))
+			require.NoError(t, err)
+
+			_, err = client.ParseWebhookEvent(tt.secret, tt.header, []byte(tt.payload))
+			require.Equal(t, tt.name == "valid", err == nil)
+			require.Equal(t, []string{tt.outcome}, metrics.outcomes)
+		})
+	}
+}
+
+func TestClient_VerifyWebhookSignature_Metrics(t *testing.T) {
+	secret := "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	payload := `{"webhook_event":"payout.complete"}`
+	id := "msg_p5jXN8AQM9LWM0D4loKWxJek"
+
+	metrics := &verificationMetrics{}
+	client, err := New("test_key", "in_000000000000", WithMetrics(metrics))
+	require.NoError(t, err)
+
+	header := signedHeader(t, secret, id, time.Time{}, payload)
+	require.True(t, client.VerifyWebhookSignature(secret, id, header.Get("Webhook-Timestamp"), payload, header.Get("Webhook-Signature")))
+
+	expired := signedHeader(t, secret, id, time.Now().Add(-time.Hour), payload)
+	require.False(t, client.VerifyWebhookSignature(secret, id, expired.Get("Webhook-Timestamp"), payload, expired.Get("Webhook-Signature")))
+
+	require.False(t, client.VerifyWebhookSignature("not a secret", id, header.Get("Webhook-Timestamp"), payload, header.Get("Webhook-Signature")))
+
+	require.Equal(t, []string{WebhookVerificationValid, WebhookVerificationInvalid, WebhookVerificationInvalid}, metrics.outcomes)
+}
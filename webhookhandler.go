@@ -0,0 +1,418 @@
+package blindpay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/blindpaylabs/blindpay-go/bankaccounts"
+	"github.com/blindpaylabs/blindpay-go/custodialwallets"
+	"github.com/blindpaylabs/blindpay-go/payins"
+	"github.com/blindpaylabs/blindpay-go/payouts"
+	"github.com/blindpaylabs/blindpay-go/receivers"
+	"github.com/blindpaylabs/blindpay-go/transfers"
+	"github.com/blindpaylabs/blindpay-go/virtualaccounts"
+	"github.com/blindpaylabs/blindpay-go/wallets"
+)
+
+// DefaultWebhookMaxBodySize is the largest webhook body accepted by a
+// WebhookHandler unless WithWebhookMaxBodySize says otherwise.
+const DefaultWebhookMaxBodySize = 1 << 20
+
+// WebhookHandlerFunc handles a verified webhook delivery.
+type WebhookHandlerFunc func(ctx context.Context, event *WebhookEnvelope) error
+
+// WebhookHandlerOption configures a WebhookHandler.
+type WebhookHandlerOption func(*WebhookHandler)
+
+// WebhookHandler is an http.Handler that receives BlindPay webhooks.
+//
+// It reads the body up to a size cap, verifies its Svix signature, parses it
+// with ParseWebhookEvent and calls the handler registered for its event type.
+// Deliveries whose timestamp is further from now than the tolerance set by
+// WithWebhookTolerance are rejected as replays. With WithWebhookDedupe, each
+// webhook-id is claimed in a DedupeStore before being dispatched, so that a
+// redelivered event is acknowledged without being processed twice.
+//
+// With WithWebhookQueue, deliveries are persisted in a WebhookQueue and
+// acknowledged before being dispatched by its workers. The queue ignores the
+// webhook-ids it already holds, so no DedupeStore is needed then.
+//
+// It answers:
+//
+//   - 202 when the delivery is queued;
+//   - 204 when the handler succeeds, when no handler is registered for the
+//     event type, or when the delivery was already processed;
+//   - 409 when another attempt of the delivery is still being processed, so
+//     that Svix delivers it again later;
+//   - 500 when the handler fails, so that Svix delivers the event again;
+//   - 401 when the signature is invalid, 400 when the body cannot be parsed,
+//     413 when it is too large and 405 for methods other than POST;
+//   - 500 when a secret is invalid, so that deliveries are retried once the
+//     handler is fixed.
+//
+// For example:
+//
+//	handler := blindpay.NewWebhookHandler(secret,
+//		blindpay.OnPayoutComplete(func(ctx context.Context, payout *payouts.Payout) error {
+//			return ledger.SettlePayout(ctx, payout.ID)
+//		}),
+//	)
+//	http.Handle("/webhooks/blindpay", handler)
+type WebhookHandler struct {
+	secrets     []string
+	maxBodySize int64
+	tolerance   time.Duration
+	dedupe      DedupeStore
+	queue       *WebhookQueue
+	logger      *slog.Logger
+	handlers    map[WebhookEvent]WebhookHandlerFunc
+	fallback    WebhookHandlerFunc
+}
+
+// NewWebhookHandler returns a handler verifying deliveries with secret, the
+// signing secret of the webhook endpoint, and any secret added by
+// WithWebhookSecrets.
+func NewWebhookHandler(secret string, opts ...WebhookHandlerOption) *WebhookHandler {
+	h := &WebhookHandler{
+		secrets:     []string{secret},
+		maxBodySize: DefaultWebhookMaxBodySize,
+		tolerance:   DefaultWebhookTolerance,
+		handlers:    make(map[WebhookEvent]WebhookHandlerFunc),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.fail(w, r, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodySize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.fail(w, r, http.StatusRequestEntityTooLarge, err)
+			return
+		}
+		h.fail(w, r, http.StatusBadRequest, fmt.Errorf("failed to read webhook body: %w", err))
+		return
+	}
+
+	event, err := parseWebhookEvent(h.secrets, r.Header, body, h.tolerance)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, ErrInvalidWebhookSignature):
+			status = http.StatusUnauthorized
+		case errors.Is(err, ErrWebhookSecret):
+			status = http.StatusInternalServerError
+		}
+		h.fail(w, r, status, err)
+		return
+	}
+
+	if h.queue != nil {
+		if err := h.queue.Enqueue(r.Context(), event); err != nil {
+			h.fail(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if h.dedupe == nil {
+		if err := h.Dispatch(r.Context(), event); err != nil {
+			h.fail(w, r, http.StatusInternalServerError, fmt.Errorf("%s webhook %s: %w", event.Type, event.ID, err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	status, token, err := h.dedupe.Claim(r.Context(), event.ID)
+	if err != nil {
+		h.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	switch status {
+	case DedupeDuplicate:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case DedupeInProgress:
+		h.fail(w, r, http.StatusConflict, fmt.Errorf("%s webhook %s is already being processed", event.Type, event.ID))
+		return
+	}
+
+	if err := h.Dispatch(r.Context(), event); err != nil {
+		// Use a fresh context: the request one may be what made the handler fail.
+		if releaseErr := h.dedupe.Release(context.WithoutCancel(r.Context()), event.ID, token); releaseErr != nil {
+			err = errors.Join(err, releaseErr)
+		}
+		h.fail(w, r, http.StatusInternalServerError, fmt.Errorf("%s webhook %s: %w", event.Type, event.ID, err))
+		return
+	}
+
+	// The event was processed: answering with an error now would only make
+	// Svix deliver it again, so a failure to record it is only logged. A lost
+	// claim means another attempt took the delivery over after the lease
+	// expired, and records its own outcome.
+	if err := h.dedupe.Complete(context.WithoutCancel(r.Context()), event.ID, token); err != nil && h.logger != nil {
+		level, msg := slog.LevelError, "blindpay webhook processed but not recorded"
+		if errors.Is(err, ErrWebhookClaimLost) {
+			level, msg = slog.LevelWarn, "blindpay webhook claim lost before it was recorded"
+		}
+		h.logger.LogAttrs(r.Context(), level, msg,
+			slog.String("webhook_id", event.ID),
+			slog.String("error", err.Error()),
+		)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Dispatch calls the handler registered for the event type, if any. It is
+// what ServeHTTP calls for each verified delivery, and what a WebhookQueue
+// runs for each queued one.
+func (h *WebhookHandler) Dispatch(ctx context.Context, event *WebhookEnvelope) error {
+	fn := h.handlers[event.Type]
+	if fn == nil {
+		fn = h.fallback
+	}
+	if fn == nil {
+		return nil
+	}
+	return fn(withWebhookEnvelope(ctx, event), event)
+}
+
+// fail answers with status and logs err.
+func (h *WebhookHandler) fail(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if h.logger != nil {
+		h.logger.LogAttrs(r.Context(), slog.LevelWarn, "blindpay webhook rejected",
+			slog.Int("status", status),
+			slog.String("error", err.Error()),
+		)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
+
+// WithWebhookMaxBodySize caps the size of the webhook bodies read by the handler.
+func WithWebhookMaxBodySize(n int64) WebhookHandlerOption {
+	return func(h *WebhookHandler) {
+		if n > 0 {
+			h.maxBodySize = n
+		}
+	}
+}
+
+// WithWebhookSecrets adds secrets accepted besides the one given to
+// NewWebhookHandler, for example the previous secret of an endpoint while it
+// is being rotated.
+func WithWebhookSecrets(secrets ...string) WebhookHandlerOption {
+	return func(h *WebhookHandler) {
+		h.secrets = append(h.secrets, secrets...)
+	}
+}
+
+// WithWebhookTolerance sets how far the webhook-timestamp of a delivery may be
+// from now. It defaults to DefaultWebhookTolerance.
+func WithWebhookTolerance(d time.Duration) WebhookHandlerOption {
+	return func(h *WebhookHandler) {
+		if d > 0 {
+			h.tolerance = d
+		}
+	}
+}
+
+// WithWebhookDedupe makes the handler record the deliveries it processes in
+// store and skip the ones already processed.
+func WithWebhookDedupe(store DedupeStore) WebhookHandlerOption {
+	return func(h *WebhookHandler) {
+		h.dedupe = store
+	}
+}
+
+// WithWebhookQueue makes the handler persist deliveries in queue and
+// acknowledge them right away, instead of dispatching them itself. The
+// deliveries are dispatched by queue.Run.
+func WithWebhookQueue(queue *WebhookQueue) WebhookHandlerOption {
+	return func(h *WebhookHandler) {
+		h.queue = queue
+	}
+}
+
+// WithWebhookLogger makes the handler log every rejected delivery to logger,
+// including deliveries whose handler failed.
+func WithWebhookLogger(logger *slog.Logger) WebhookHandlerOption {
+	return func(h *WebhookHandler) {
+		h.logger = logger
+	}
+}
+
+// OnWebhookEvent registers fn for the given event type, replacing any handler
+// registered for it before.
+func OnWebhookEvent(event WebhookEvent, fn WebhookHandlerFunc) WebhookHandlerOption {
+	return func(h *WebhookHandler) {
+		h.handlers[event] = fn
+	}
+}
+
+// OnOtherWebhookEvent registers fn for the event types that have no handler
+// of their own.
+func OnOtherWebhookEvent(fn WebhookHandlerFunc) WebhookHandlerOption {
+	return func(h *WebhookHandler) {
+		h.fallback = fn
+	}
+}
+
+type webhookEnvelopeKey struct{}
+
+// withWebhookEnvelope returns a context carrying event.
+func withWebhookEnvelope(ctx context.Context, event *WebhookEnvelope) context.Context {
+	return context.WithValue(ctx, webhookEnvelopeKey{}, event)
+}
+
+// WebhookEnvelopeFromContext returns the delivery being handled, for typed
+// handlers that need its ID or timestamp.
+func WebhookEnvelopeFromContext(ctx context.Context) (*WebhookEnvelope, bool) {
+	event, ok := ctx.Value(webhookEnvelopeKey{}).(*WebhookEnvelope)
+	return event, ok
+}
+
+// onTyped registers a handler receiving the payload of event decoded into T.
+func onTyped[T any](event WebhookEvent, fn func(context.Context, *T) error) WebhookHandlerOption {
+	return OnWebhookEvent(event, func(ctx context.Context, envelope *WebhookEnvelope) error {
+		data, ok := envelope.Data.(*T)
+		if !ok {
+			return fmt.Errorf("unexpected %T payload for %s", envelope.Data, envelope.Type)
+		}
+		return fn(ctx, data)
+	})
+}
+
+// onRaw registers a handler receiving the raw payload of event.
+func onRaw(event WebhookEvent, fn func(context.Context, json.RawMessage) error) WebhookHandlerOption {
+	return OnWebhookEvent(event, func(ctx context.Context, envelope *WebhookEnvelope) error {
+		return fn(ctx, envelope.Raw)
+	})
+}
+
+// OnReceiverNew registers fn for receiver.new events.
+func OnReceiverNew(fn func(context.Context, *receivers.Receiver) error) WebhookHandlerOption {
+	return onTyped(WebhookEventReceiverNew, fn)
+}
+
+// OnReceiverUpdate registers fn for receiver.update events.
+func OnReceiverUpdate(fn func(context.Context, *receivers.Receiver) error) WebhookHandlerOption {
+	return onTyped(WebhookEventReceiverUpdate, fn)
+}
+
+// OnBankAccountNew registers fn for bankAccount.new events.
+func OnBankAccountNew(fn func(context.Context, *bankaccounts.BankAccount) error) WebhookHandlerOption {
+	return onTyped(WebhookEventBankAccountNew, fn)
+}
+
+// OnPayoutNew registers fn for payout.new events.
+func OnPayoutNew(fn func(context.Context, *payouts.Payout) error) WebhookHandlerOption {
+	return onTyped(WebhookEventPayoutNew, fn)
+}
+
+// OnPayoutUpdate registers fn for payout.update events.
+func OnPayoutUpdate(fn func(context.Context, *payouts.Payout) error) WebhookHandlerOption {
+	return onTyped(WebhookEventPayoutUpdate, fn)
+}
+
+// OnPayoutComplete registers fn for payout.complete events.
+func OnPayoutComplete(fn func(context.Context, *payouts.Payout) error) WebhookHandlerOption {
+	return onTyped(WebhookEventPayoutComplete, fn)
+}
+
+// OnPayoutPartnerFee registers fn for payout.partnerFee events.
+func OnPayoutPartnerFee(fn func(context.Context, *payouts.Payout) error) WebhookHandlerOption {
+	return onTyped(WebhookEventPayoutPartnerFee, fn)
+}
+
+// OnBlockchainWalletNew registers fn for blockchainWallet.new events.
+func OnBlockchainWalletNew(fn func(context.Context, *wallets.BlockchainWallet) error) WebhookHandlerOption {
+	return onTyped(WebhookEventBlockchainWalletNew, fn)
+}
+
+// OnPayinNew registers fn for payin.new events.
+func OnPayinNew(fn func(context.Context, *payins.Payin) error) WebhookHandlerOption {
+	return onTyped(WebhookEventPayinNew, fn)
+}
+
+// OnPayinUpdate registers fn for payin.update events.
+func OnPayinUpdate(fn func(context.Context, *payins.Payin) error) WebhookHandlerOption {
+	return onTyped(WebhookEventPayinUpdate, fn)
+}
+
+// OnPayinComplete registers fn for payin.complete events.
+func OnPayinComplete(fn func(context.Context, *payins.Payin) error) WebhookHandlerOption {
+	return onTyped(WebhookEventPayinComplete, fn)
+}
+
+// OnPayinPartnerFee registers fn for payin.partnerFee events.
+func OnPayinPartnerFee(fn func(context.Context, *payins.Payin) error) WebhookHandlerOption {
+	return onTyped(WebhookEventPayinPartnerFee, fn)
+}
+
+// OnTosAccept registers fn for tos.accept events, which have no SDK model.
+func OnTosAccept(fn func(context.Context, json.RawMessage) error) WebhookHandlerOption {
+	return onRaw(WebhookEventTosAccept, fn)
+}
+
+// OnLimitIncreaseNew registers fn for limitIncrease.new events.
+func OnLimitIncreaseNew(fn func(context.Context, *receivers.LimitIncreaseRequest) error) WebhookHandlerOption {
+	return onTyped(WebhookEventLimitIncreaseNew, fn)
+}
+
+// OnLimitIncreaseUpdate registers fn for limitIncrease.update events.
+func OnLimitIncreaseUpdate(fn func(context.Context, *receivers.LimitIncreaseRequest) error) WebhookHandlerOption {
+	return onTyped(WebhookEventLimitIncreaseUpdate, fn)
+}
+
+// OnVirtualAccountNew registers fn for virtualAccount.new events.
+func OnVirtualAccountNew(fn func(context.Context, *virtualaccounts.VirtualAccount) error) WebhookHandlerOption {
+	return onTyped(WebhookEventVirtualAccountNew, fn)
+}
+
+// OnVirtualAccountComplete registers fn for virtualAccount.complete events.
+func OnVirtualAccountComplete(fn func(context.Context, *virtualaccounts.VirtualAccount) error) WebhookHandlerOption {
+	return onTyped(WebhookEventVirtualAccountComplete, fn)
+}
+
+// OnTransferNew registers fn for transfer.new events.
+func OnTransferNew(fn func(context.Context, *transfers.Transfer) error) WebhookHandlerOption {
+	return onTyped(WebhookEventTransferNew, fn)
+}
+
+// OnTransferUpdate registers fn for transfer.update events.
+func OnTransferUpdate(fn func(context.Context, *transfers.Transfer) error) WebhookHandlerOption {
+	return onTyped(WebhookEventTransferUpdate, fn)
+}
+
+// OnTransferComplete registers fn for transfer.complete events.
+func OnTransferComplete(fn func(context.Context, *transfers.Transfer) error) WebhookHandlerOption {
+	return onTyped(WebhookEventTransferComplete, fn)
+}
+
+// OnWalletNew registers fn for wallet.new events.
+func OnWalletNew(fn func(context.Context, *custodialwallets.CustodialWallet) error) WebhookHandlerOption {
+	return onTyped(WebhookEventWalletNew, fn)
+}
+
+// OnWalletInbound registers fn for wallet.inbound events, which have no SDK model.
+func OnWalletInbound(fn func(context.Context, json.RawMessage) error) WebhookHandlerOption {
+	return onRaw(WebhookEventWalletInbound, fn)
+}
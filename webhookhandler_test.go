@@ -0,0 +1,149 @@
+package blindpay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blindpaylabs/blindpay-go/payouts"
+)
+
+// deliver sends a signed delivery of payload to handler.
+func deliver(t *testing.T, handler http.Handler, secret, payload string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(payload))
+	for key, values := range signedHeader(t, secret, "msg_000000000000", time.Time{}, payload) {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhookHandler(t *testing.T) {
+	secret := "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	payload := `{"webhook_event":"payout.complete","data":{"id":"pa_000000000000","status":"completed"}}`
+
+	t.Run("dispatches typed payloads", func(t *testing.T) {
+		var got *payouts.Payout
+		var envelope *WebhookEnvelope
+		handler := NewWebhookHandler(secret,
+			OnPayoutComplete(func(ctx context.Context, payout *payouts.Payout) error {
+				got = payout
+				envelope, _ = WebhookEnvelopeFromContext(ctx)
+				return nil
+			}),
+		)
+
+		rec := deliver(t, handler, secret, payload)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+		require.NotNil(t, got)
+		require.Equal(t, "pa_000000000000", got.ID)
+		require.NotNil(t, envelope)
+		require.Equal(t, "msg_000000000000", envelope.ID)
+	})
+
+	t.Run("acknowledges events without handler", func(t *testing.T) {
+		handler := NewWebhookHandler(secret)
+
+		rec := deliver(t, handler, secret, payload)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("falls back for other events", func(t *testing.T) {
+		var got WebhookEvent
+		handler := NewWebhookHandler(secret,
+			OnOtherWebhookEvent(func(ctx context.Context, event *WebhookEnvelope) error {
+				got = event.Type
+				return nil
+			}),
+		)
+
+		rec := deliver(t, handler, secret, `{"webhook_event":"tos.accept","data":{"id":"to_000000000000"}}`)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+		require.Equal(t, WebhookEventTosAccept, got)
+	})
+
+	t.Run("raw payloads", func(t *testing.T) {
+		var got json.RawMessage
+		handler := NewWebhookHandler(secret,
+			OnWalletInbound(func(ctx context.Context, data json.RawMessage) error {
+				got = data
+				return nil
+			}),
+		)
+
+		rec := deliver(t, handler, secret, `{"webhook_event":"wallet.inbound","data":{"id":"cw_000000000000"}}`)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+		require.JSONEq(t, `{"id":"cw_000000000000"}`, string(got))
+	})
+
+	t.Run("handler error", func(t *testing.T) {
+		handler := NewWebhookHandler(secret,
+			OnPayoutComplete(func(ctx context.Context, payout *payouts.Payout) error {
+				return errors.New("ledger unavailable")
+			}),
+		)
+
+		rec := deliver(t, handler, secret, payload)
+		require.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		called := false
+		handler := NewWebhookHandler(secret,
+			OnPayoutComplete(func(ctx context.Context, payout *payouts.Payout) error {
+				called = true
+				return nil
+			}),
+		)
+
+		rec := deliver(t, handler, "whsec_dGhpcyBpcyBhbm90aGVyIHNlY3JldA==", payload)
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+		require.False(t, called)
+	})
+
+	t.Run("rotated secret", func(t *testing.T) {
+		newSecret := "whsec_dGhpcyBpcyBhbm90aGVyIHNlY3JldA=="
+		handler := NewWebhookHandler(newSecret, WithWebhookSecrets(secret))
+
+		require.Equal(t, http.StatusNoContent, deliver(t, handler, secret, payload).Code)
+		require.Equal(t, http.StatusNoContent, deliver(t, handler, newSecret, payload).Code)
+	})
+
+	t.Run("invalid secret", func(t *testing.T) {
+		handler := NewWebhookHandler("whsec_not base64!")
+
+		rec := deliver(t, handler, secret, payload)
+		require.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("missing event type", func(t *testing.T) {
+		handler := NewWebhookHandler(secret)
+
+		rec := deliver(t, handler, secret, `{"data":{}}`)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		handler := NewWebhookHandler(secret, WithWebhookMaxBodySize(16))
+
+		rec := deliver(t, handler, secret, payload)
+		require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		handler := NewWebhookHandler(secret)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhooks", nil))
+		require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		require.Equal(t, http.MethodPost, rec.Header().Get("Allow"))
+	})
+}
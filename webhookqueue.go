@@ -0,0 +1,422 @@
+package blindpay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// QueuedWebhookStatus is the processing state of a queued webhook delivery.
+type QueuedWebhookStatus string
+
+const (
+	// QueuedWebhookPending deliveries wait for their next attempt.
+	QueuedWebhookPending QueuedWebhookStatus = "pending"
+	// QueuedWebhookProcessing deliveries are being handled by a worker.
+	QueuedWebhookProcessing QueuedWebhookStatus = "processing"
+	// QueuedWebhookCompleted deliveries were handled successfully.
+	QueuedWebhookCompleted QueuedWebhookStatus = "completed"
+	// QueuedWebhookDead deliveries failed every attempt allowed by their
+	// retry policy, or could not be decoded. They wait in the dead-letter
+	// store until replayed.
+	QueuedWebhookDead QueuedWebhookStatus = "dead"
+)
+
+// ErrDeadLetterNotFound is returned by Replay when no dead-lettered delivery
+// has the given ID.
+var ErrDeadLetterNotFound = errors.New("blindpay: dead-lettered webhook not found")
+
+// ErrWebhookClaimLost is returned when the outcome of a claim on a delivery is
+// recorded after the claim was lost: its lease expired and another worker or
+// attempt took the delivery over, or its outcome was already recorded. It is
+// returned by the Complete, Retry and DeadLetter methods of a
+// WebhookQueueStore, and the Complete and Release methods of a DedupeStore.
+var ErrWebhookClaimLost = errors.New("blindpay: webhook claim lost")
+
+// QueuedWebhook is a verified webhook delivery persisted by a WebhookQueue.
+type QueuedWebhook struct {
+	// ID is the webhook-id of the delivery.
+	ID string
+	// Type is the event type of the delivery.
+	Type WebhookEvent
+	// Timestamp is the signed webhook-timestamp of the delivery.
+	Timestamp time.Time
+	// Payload is the Raw payload of the delivery.
+	Payload json.RawMessage
+	// Status is the processing state of the delivery.
+	Status QueuedWebhookStatus
+	// Attempts counts the attempts made to handle the delivery.
+	Attempts int
+	// NextAttemptAt is when a pending delivery is due, or when the claim of
+	// a processing delivery expires.
+	NextAttemptAt time.Time
+	// LastError is the error of the last failed attempt.
+	LastError string
+	// CreatedAt is when the delivery was queued.
+	CreatedAt time.Time
+	// UpdatedAt is when the delivery last changed state.
+	UpdatedAt time.Time
+}
+
+// DeadLetterFilter selects dead-lettered deliveries.
+type DeadLetterFilter struct {
+	// Type, when set, only selects deliveries of this event type.
+	Type WebhookEvent
+	// Since, when set, only selects deliveries dead-lettered at or after it.
+	Since time.Time
+	// Limit caps the number of deliveries returned. It defaults to 100.
+	Limit int
+}
+
+// limit returns the effective limit of the filter.
+func (f DeadLetterFilter) limit() int {
+	if f.Limit <= 0 {
+		return 100
+	}
+	return f.Limit
+}
+
+// WebhookQueueStore persists the deliveries of a WebhookQueue. Implementations
+// must be safe for concurrent use, by several processes when the store is
+// shared, and Claim must hand a delivery to a single caller.
+//
+// Complete, Retry and DeadLetter record the outcome of the claim that counted
+// attempt, the Attempts of the delivery returned by Claim. Unless the delivery
+// is still processing under that attempt, they change nothing and return
+// ErrWebhookClaimLost, so that a worker whose lease expired cannot override
+// the worker that claimed the delivery after it.
+type WebhookQueueStore interface {
+	// Enqueue stores a pending delivery. It does nothing when a delivery
+	// with the same ID is already stored, so that Svix redeliveries are
+	// queued once.
+	Enqueue(ctx context.Context, delivery *QueuedWebhook) error
+	// Claim returns the due delivery with the earliest NextAttemptAt, after
+	// marking it processing until now plus lease and counting an attempt.
+	// Pending deliveries are due once NextAttemptAt is reached, and so are
+	// processing ones whose claim expired. It returns nil when none is due.
+	Claim(ctx context.Context, now time.Time, lease time.Duration) (*QueuedWebhook, error)
+	// Complete marks the delivery id as completed.
+	Complete(ctx context.Context, id string, attempt int) error
+	// Retry makes the delivery id pending again until next, recording the
+	// error of its last attempt.
+	Retry(ctx context.Context, id string, attempt int, next time.Time, lastError string) error
+	// DeadLetter moves the delivery id to the dead-letter store.
+	DeadLetter(ctx context.Context, id string, attempt int, lastError string) error
+	// DeadLetters returns the dead-lettered deliveries selected by filter,
+	// oldest first.
+	DeadLetters(ctx context.Context, filter DeadLetterFilter) ([]QueuedWebhook, error)
+	// Replay makes the dead-lettered delivery id pending again with no
+	// attempt counted, due at now. It returns ErrDeadLetterNotFound when no
+	// dead-lettered delivery has this ID.
+	Replay(ctx context.Context, id string, now time.Time) error
+}
+
+// DefaultWebhookQueueRetryPolicy returns the retry policy applied by a
+// WebhookQueue to the event types without a policy of their own: up to 8
+// attempts, 10 seconds apart at first and up to 15 minutes apart.
+// Only the MaxAttempts, BaseDelay, MaxDelay and Jitter fields of a
+// RetryPolicy apply to queued deliveries.
+func DefaultWebhookQueueRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 8,
+		BaseDelay:   10 * time.Second,
+		MaxDelay:    15 * time.Minute,
+		Jitter:      0.2,
+	}
+}
+
+// WebhookQueueOption configures a WebhookQueue.
+type WebhookQueueOption func(*WebhookQueue)
+
+// WebhookQueue processes webhook deliveries asynchronously. A WebhookHandler
+// configured with WithWebhookQueue verifies and persists each delivery, then
+// acknowledges it right away; Run hands the queued deliveries to a bounded
+// pool of workers, retries the failed ones with backoff and moves the ones
+// that keep failing to the dead-letter store.
+//
+//	queue := blindpay.NewWebhookQueue(store,
+//		blindpay.WithWebhookQueueRetry(blindpay.WebhookEventPayinComplete, blindpay.RetryPolicy{
+//			MaxAttempts: 20,
+//			BaseDelay:   time.Minute,
+//			MaxDelay:    time.Hour,
+//		}),
+//	)
+//	handler := blindpay.NewWebhookHandler(secret,
+//		blindpay.WithWebhookQueue(queue),
+//		blindpay.OnPayinComplete(creditPayin),
+//	)
+//	go queue.Run(ctx, handler.Dispatch)
+//
+// Deliveries are processed at least once: a delivery whose worker died is
+// claimed again once its lease expires.
+type WebhookQueue struct {
+	store        WebhookQueueStore
+	workers      int
+	lease        time.Duration
+	pollInterval time.Duration
+	retry        RetryPolicy
+	retries      map[WebhookEvent]RetryPolicy
+	logger       *slog.Logger
+	now          func() time.Time
+	wake         chan struct{}
+}
+
+// NewWebhookQueue returns a queue persisting deliveries in store. It runs 4
+// workers, claims deliveries for 5 minutes and polls the store every second
+// unless options say otherwise.
+func NewWebhookQueue(store WebhookQueueStore, opts ...WebhookQueueOption) *WebhookQueue {
+	q := &WebhookQueue{
+		store:        store,
+		workers:      4,
+		lease:        5 * time.Minute,
+		pollInterval: time.Second,
+		retry:        DefaultWebhookQueueRetryPolicy(),
+		retries:      make(map[WebhookEvent]RetryPolicy),
+		now:          time.Now,
+		wake:         make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// WithWebhookQueueWorkers sets how many deliveries are processed at once.
+func WithWebhookQueueWorkers(n int) WebhookQueueOption {
+	return func(q *WebhookQueue) {
+		if n > 0 {
+			q.workers = n
+		}
+	}
+}
+
+// WithWebhookQueueLease sets how long a worker owns a delivery before another
+// worker may claim it again. It should exceed the longest handler run.
+func WithWebhookQueueLease(d time.Duration) WebhookQueueOption {
+	return func(q *WebhookQueue) {
+		if d > 0 {
+			q.lease = d
+		}
+	}
+}
+
+// WithWebhookQueuePollInterval sets how often idle workers look for due
+// deliveries, such as retries or deliveries queued by other processes.
+func WithWebhookQueuePollInterval(d time.Duration) WebhookQueueOption {
+	return func(q *WebhookQueue) {
+		if d > 0 {
+			q.pollInterval = d
+		}
+	}
+}
+
+// WithWebhookQueueDefaultRetry sets the retry policy of the event types
+// without a policy of their own.
+func WithWebhookQueueDefaultRetry(policy RetryPolicy) WebhookQueueOption {
+	return func(q *WebhookQueue) {
+		q.retry = policy
+	}
+}
+
+// WithWebhookQueueRetry sets the retry policy of an event type.
+func WithWebhookQueueRetry(event WebhookEvent, policy RetryPolicy) WebhookQueueOption {
+	return func(q *WebhookQueue) {
+		q.retries[event] = policy
+	}
+}
+
+// WithWebhookQueueLogger makes the queue log failed attempts, dead-lettered
+// deliveries and store errors to logger.
+func WithWebhookQueueLogger(logger *slog.Logger) WebhookQueueOption {
+	return func(q *WebhookQueue) {
+		q.logger = logger
+	}
+}
+
+// Enqueue persists a verified delivery and wakes a worker to process it.
+func (q *WebhookQueue) Enqueue(ctx context.Context, event *WebhookEnvelope) error {
+	now := q.now()
+	err := q.store.Enqueue(ctx, &QueuedWebhook{
+		ID:            event.ID,
+		Type:          event.Type,
+		Timestamp:     event.Timestamp,
+		Payload:       event.Raw,
+		Status:        QueuedWebhookPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue %s webhook %s: %w", event.Type, event.ID, err)
+	}
+
+	q.signal()
+	return nil
+}
+
+// DeadLetters returns the dead-lettered deliveries selected by filter.
+func (q *WebhookQueue) DeadLetters(ctx context.Context, filter DeadLetterFilter) ([]QueuedWebhook, error) {
+	return q.store.DeadLetters(ctx, filter)
+}
+
+// Replay queues the dead-lettered delivery id again, with a fresh set of
+// attempts.
+func (q *WebhookQueue) Replay(ctx context.Context, id string) error {
+	if err := q.store.Replay(ctx, id, q.now()); err != nil {
+		return err
+	}
+
+	q.signal()
+	return nil
+}
+
+// Run processes queued deliveries with dispatch, usually the Dispatch method
+// of a WebhookHandler, until ctx is done. It then waits for the deliveries
+// being processed, whose handlers are not cancelled, and returns ctx.Err().
+func (q *WebhookQueue) Run(ctx context.Context, dispatch WebhookHandlerFunc) error {
+	var wg sync.WaitGroup
+	for i := 0; i < q.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work(ctx, dispatch)
+		}()
+	}
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// work claims and processes deliveries until ctx is done.
+func (q *WebhookQueue) work(ctx context.Context, dispatch WebhookHandlerFunc) {
+	for {
+		delivery, err := q.store.Claim(ctx, q.now(), q.lease)
+		if err != nil && ctx.Err() == nil {
+			q.log(ctx, slog.LevelError, "blindpay webhook queue claim failed", "", err)
+		}
+		if delivery != nil {
+			q.process(context.WithoutCancel(ctx), delivery, dispatch)
+			continue
+		}
+
+		idle := time.NewTimer(q.pollInterval)
+		select {
+		case <-ctx.Done():
+			idle.Stop()
+			return
+		case <-q.wake:
+		case <-idle.C:
+		}
+		idle.Stop()
+	}
+}
+
+// process handles a claimed delivery and records the outcome.
+func (q *WebhookQueue) process(ctx context.Context, delivery *QueuedWebhook, dispatch WebhookHandlerFunc) {
+	event := &WebhookEnvelope{
+		Type:      delivery.Type,
+		ID:        delivery.ID,
+		Timestamp: delivery.Timestamp,
+		Raw:       delivery.Payload,
+	}
+
+	err := decodeWebhookData(event)
+	if err != nil {
+		// Decoding fails the same way on every attempt.
+		q.deadLetter(ctx, delivery, err)
+		return
+	}
+
+	if err = safeDispatch(ctx, dispatch, event); err == nil {
+		if err := q.store.Complete(ctx, delivery.ID, delivery.Attempts); err != nil {
+			q.recordFailed(ctx, "blindpay webhook processed but not recorded", delivery.ID, err)
+		}
+		return
+	}
+
+	policy, ok := q.retries[delivery.Type]
+	if !ok {
+		policy = q.retry
+	}
+	if delivery.Attempts >= max(policy.MaxAttempts, 1) {
+		q.deadLetter(ctx, delivery, err)
+		return
+	}
+
+	next := q.now().Add(queueBackoff(policy, delivery.Attempts))
+	q.log(ctx, slog.LevelWarn, "blindpay webhook attempt failed", delivery.ID, err)
+	if err := q.store.Retry(ctx, delivery.ID, delivery.Attempts, next, err.Error()); err != nil {
+		q.recordFailed(ctx, "blindpay webhook retry not recorded", delivery.ID, err)
+	}
+}
+
+// queueBackoff returns the delay before the next attempt of a delivery that
+// failed its given attempt (1 for the first attempt): BaseDelay doubled on
+// every attempt, capped at MaxDelay and shortened by up to Jitter.
+func queueBackoff(policy RetryPolicy, attempt int) time.Duration {
+	delay := float64(policy.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if policy.MaxDelay > 0 && delay > float64(policy.MaxDelay) {
+		delay = float64(policy.MaxDelay)
+	}
+
+	if policy.Jitter > 0 {
+		delay -= delay * math.Min(policy.Jitter, 1) * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// deadLetter moves a delivery to the dead-letter store.
+func (q *WebhookQueue) deadLetter(ctx context.Context, delivery *QueuedWebhook, err error) {
+	q.log(ctx, slog.LevelError, "blindpay webhook dead-lettered", delivery.ID, err)
+	if err := q.store.DeadLetter(ctx, delivery.ID, delivery.Attempts, err.Error()); err != nil {
+		q.recordFailed(ctx, "blindpay webhook dead letter not recorded", delivery.ID, err)
+	}
+}
+
+// recordFailed logs the failure to record the outcome of the delivery id. A
+// lost claim is only a warning: the worker that took the delivery over
+// records its own outcome.
+func (q *WebhookQueue) recordFailed(ctx context.Context, msg, id string, err error) {
+	if errors.Is(err, ErrWebhookClaimLost) {
+		q.log(ctx, slog.LevelWarn, "blindpay webhook claim lost before its outcome was recorded", id, err)
+		return
+	}
+	q.log(ctx, slog.LevelError, msg, id, err)
+}
+
+// signal wakes an idle worker, if any.
+func (q *WebhookQueue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// log logs err about the delivery id, when the queue has a logger.
+func (q *WebhookQueue) log(ctx context.Context, level slog.Level, msg, id string, err error) {
+	if q.logger == nil {
+		return
+	}
+	attrs := []slog.Attr{slog.String("error", err.Error())}
+	if id != "" {
+		attrs = append(attrs, slog.String("webhook_id", id))
+	}
+	q.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// safeDispatch calls dispatch, turning a panic into an error so that a
+// faulty handler does not stop the worker.
+func safeDispatch(ctx context.Context, dispatch WebhookHandlerFunc, event *WebhookEnvelope) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in %s webhook handler: %v", event.Type, r)
+		}
+	}()
+	return dispatch(ctx, event)
+}
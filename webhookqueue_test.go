@@ -0,0 +1,366 @@
+package blindpay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blindpaylabs/blindpay-go/payouts"
+)
+
+func TestWebhookQueueStores(t *testing.T) {
+	ctx := context.Background()
+
+	stores := map[string]func(t *testing.T) WebhookQueueStore{
+		"memory": func(t *testing.T) WebhookQueueStore {
+			return NewMemoryWebhookQueueStore()
+		},
+		"sql": func(t *testing.T) WebhookQueueStore {
+			store, err := NewSQLWebhookQueueStore(openSQLDB(t), "webhook_queue", SQLPlaceholderQuestion)
+			require.NoError(t, err)
+			require.NoError(t, store.CreateTable(ctx))
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore(t)
+			clock := time.UnixMilli(1700000000000)
+			queued := func(id string, event WebhookEvent) *QueuedWebhook {
+				return &QueuedWebhook{
+					ID:            id,
+					Type:          event,
+					Timestamp:     clock,
+					Payload:       []byte(`{"id":"pa_000000000000"}`),
+					Status:        QueuedWebhookPending,
+					NextAttemptAt: clock,
+					CreatedAt:     clock,
+					UpdatedAt:     clock,
+				}
+			}
+
+			require.NoError(t, store.Enqueue(ctx, queued("msg_1", WebhookEventPayoutComplete)))
+			require.NoError(t, store.Enqueue(ctx, queued("msg_1", WebhookEventPayoutUpdate)))
+
+			claimed, err := store.Claim(ctx, clock, time.Minute)
+			require.NoError(t, err)
+			require.NotNil(t, claimed)
+			require.Equal(t, "msg_1", claimed.ID)
+			require.Equal(t, WebhookEventPayoutComplete, claimed.Type)
+			require.JSONEq(t, `{"id":"pa_000000000000"}`, string(claimed.Payload))
+			require.True(t, clock.Equal(claimed.Timestamp))
+			require.Equal(t, QueuedWebhookProcessing, claimed.Status)
+			require.Equal(t, 1, claimed.Attempts)
+
+			// A claimed delivery is claimed again once its lease expires.
+			claimed, err = store.Claim(ctx, clock, time.Minute)
+			require.NoError(t, err)
+			require.Nil(t, claimed)
+			clock = clock.Add(time.Minute)
+			claimed, err = store.Claim(ctx, clock, time.Minute)
+			require.NoError(t, err)
+			require.NotNil(t, claimed)
+			require.Equal(t, 2, claimed.Attempts)
+
+			// The worker whose lease expired no longer owns the delivery.
+			require.ErrorIs(t, store.Complete(ctx, "msg_1", 1), ErrWebhookClaimLost)
+			require.ErrorIs(t, store.DeadLetter(ctx, "msg_1", 1, "gave up"), ErrWebhookClaimLost)
+			require.ErrorIs(t, store.Complete(ctx, "msg_3", 1), ErrWebhookClaimLost)
+
+			// A retried delivery is due at the given time.
+			require.NoError(t, store.Retry(ctx, "msg_1", 2, clock.Add(time.Hour), "ledger unavailable"))
+			require.ErrorIs(t, store.Retry(ctx, "msg_1", 2, clock, "ledger unavailable"), ErrWebhookClaimLost)
+			claimed, err = store.Claim(ctx, clock, time.Minute)
+			require.NoError(t, err)
+			require.Nil(t, claimed)
+			claimed, err = store.Claim(ctx, clock.Add(time.Hour), time.Minute)
+			require.NoError(t, err)
+			require.NotNil(t, claimed)
+			require.Equal(t, 3, claimed.Attempts)
+			require.Equal(t, "ledger unavailable", claimed.LastError)
+
+			require.NoError(t, store.DeadLetter(ctx, "msg_1", 3, "gave up"))
+			require.NoError(t, store.Enqueue(ctx, queued("msg_2", WebhookEventPayinComplete)))
+			claimed, err = store.Claim(ctx, clock, time.Minute)
+			require.NoError(t, err)
+			require.NotNil(t, claimed)
+			require.NoError(t, store.DeadLetter(ctx, "msg_2", 1, "gave up"))
+
+			dead, err := store.DeadLetters(ctx, DeadLetterFilter{})
+			require.NoError(t, err)
+			require.Len(t, dead, 2)
+			require.Equal(t, QueuedWebhookDead, dead[0].Status)
+			require.Equal(t, "gave up", dead[0].LastError)
+			dead, err = store.DeadLetters(ctx, DeadLetterFilter{Type: WebhookEventPayinComplete})
+			require.NoError(t, err)
+			require.Len(t, dead, 1)
+			require.Equal(t, "msg_2", dead[0].ID)
+			dead, err = store.DeadLetters(ctx, DeadLetterFilter{Limit: 1})
+			require.NoError(t, err)
+			require.Len(t, dead, 1)
+
+			// A replayed delivery starts over.
+			require.ErrorIs(t, store.Replay(ctx, "msg_3", clock), ErrDeadLetterNotFound)
+			require.NoError(t, store.Replay(ctx, "msg_1", clock))
+			require.ErrorIs(t, store.Replay(ctx, "msg_1", clock), ErrDeadLetterNotFound)
+			claimed, err = store.Claim(ctx, clock, time.Minute)
+			require.NoError(t, err)
+			require.NotNil(t, claimed)
+			require.Equal(t, "msg_1", claimed.ID)
+			require.Equal(t, 1, claimed.Attempts)
+
+			require.NoError(t, store.Complete(ctx, "msg_1", 1))
+			require.ErrorIs(t, store.Complete(ctx, "msg_1", 1), ErrWebhookClaimLost)
+			claimed, err = store.Claim(ctx, clock.Add(time.Hour), time.Minute)
+			require.NoError(t, err)
+			require.Nil(t, claimed)
+
+			// Completed deliveries still absorb redeliveries.
+			require.NoError(t, store.Enqueue(ctx, queued("msg_1", WebhookEventPayoutComplete)))
+			claimed, err = store.Claim(ctx, clock.Add(time.Hour), time.Minute)
+			require.NoError(t, err)
+			require.Nil(t, claimed)
+
+			// Both stores prune completed deliveries the same way.
+			pruner := store.(interface {
+				Prune(ctx context.Context, before time.Time) (int64, error)
+			})
+			n, err := pruner.Prune(ctx, time.Now().Add(time.Minute))
+			require.NoError(t, err)
+			require.Equal(t, int64(1), n)
+		})
+	}
+}
+
+func TestSQLWebhookQueueStore(t *testing.T) {
+	_, err := NewSQLWebhookQueueStore(openSQLDB(t), "queue; DROP TABLE users", SQLPlaceholderQuestion)
+	require.Error(t, err)
+	_, err = NewSQLWebhookQueueStore(nil, "webhook_queue", SQLPlaceholderQuestion)
+	require.Error(t, err)
+
+	ctx := context.Background()
+	store, err := NewSQLWebhookQueueStore(openSQLDB(t), "webhook_queue", SQLPlaceholderQuestion)
+	require.NoError(t, err)
+	now := time.Now()
+	require.NoError(t, store.Enqueue(ctx, &QueuedWebhook{ID: "msg_1", Type: WebhookEventPayoutComplete, Status: QueuedWebhookPending, NextAttemptAt: now}))
+	_, err = store.Claim(ctx, now, time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, store.Complete(ctx, "msg_1", 1))
+
+	n, err := store.Prune(ctx, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+}
+
+// runQueue runs queue with dispatch until the test ends.
+func runQueue(t *testing.T, queue *WebhookQueue, dispatch WebhookHandlerFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		queue.Run(ctx, dispatch)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func TestWebhookQueue(t *testing.T) {
+	ctx := context.Background()
+	event := func(id string, eventType WebhookEvent) *WebhookEnvelope {
+		return &WebhookEnvelope{
+			Type:      eventType,
+			ID:        id,
+			Timestamp: time.Now(),
+			Raw:       []byte(`{"id":"pa_000000000000","status":"completed"}`),
+		}
+	}
+	fast := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	t.Run("dispatches and retries deliveries", func(t *testing.T) {
+		store := NewMemoryWebhookQueueStore()
+		queue := NewWebhookQueue(store, WithWebhookQueueDefaultRetry(fast), WithWebhookQueuePollInterval(time.Millisecond))
+
+		done := make(chan string)
+		calls := 0
+		handler := NewWebhookHandler("whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw",
+			OnPayoutComplete(func(ctx context.Context, payout *payouts.Payout) error {
+				calls++
+				if calls < 3 {
+					return errors.New("ledger unavailable")
+				}
+				done <- payout.ID
+				return nil
+			}),
+		)
+		runQueue(t, queue, handler.Dispatch)
+
+		require.NoError(t, queue.Enqueue(ctx, event("msg_1", WebhookEventPayoutComplete)))
+		select {
+		case id := <-done:
+			require.Equal(t, "pa_000000000000", id)
+		case <-time.After(5 * time.Second):
+			t.Fatal("delivery not dispatched")
+		}
+		require.Eventually(t, func() bool {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+			return store.deliveries["msg_1"].Status == QueuedWebhookCompleted
+		}, 5*time.Second, time.Millisecond)
+	})
+
+	t.Run("dead-letters and replays deliveries", func(t *testing.T) {
+		store := NewMemoryWebhookQueueStore()
+		queue := NewWebhookQueue(store,
+			WithWebhookQueueDefaultRetry(fast),
+			WithWebhookQueueRetry(WebhookEventPayinComplete, RetryPolicy{MaxAttempts: 1}),
+			WithWebhookQueuePollInterval(time.Millisecond),
+		)
+
+		var mu sync.Mutex
+		attempts := make(map[string]int)
+		fail := true
+		runQueue(t, queue, func(ctx context.Context, event *WebhookEnvelope) error {
+			mu.Lock()
+			defer mu.Unlock()
+			attempts[event.ID]++
+			if fail {
+				panic("ledger unavailable")
+			}
+			return nil
+		})
+
+		require.NoError(t, queue.Enqueue(ctx, event("msg_1", WebhookEventPayoutComplete)))
+		require.NoError(t, queue.Enqueue(ctx, event("msg_2", WebhookEventPayinComplete)))
+		var dead []QueuedWebhook
+		require.Eventually(t, func() bool {
+			var err error
+			dead, err = queue.DeadLetters(ctx, DeadLetterFilter{})
+			return err == nil && len(dead) == 2
+		}, 5*time.Second, time.Millisecond)
+		require.Contains(t, dead[0].LastError, "ledger unavailable")
+
+		mu.Lock()
+		require.Equal(t, map[string]int{"msg_1": 3, "msg_2": 1}, attempts)
+		fail = false
+		mu.Unlock()
+
+		require.NoError(t, queue.Replay(ctx, "msg_1"))
+		require.ErrorIs(t, queue.Replay(ctx, "msg_1"), ErrDeadLetterNotFound)
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return attempts["msg_1"] == 4
+		}, 5*time.Second, time.Millisecond)
+	})
+
+	t.Run("ignores the outcome of an expired claim", func(t *testing.T) {
+		store := &retryingQueueStore{MemoryWebhookQueueStore: NewMemoryWebhookQueueStore(), retries: make(chan error, 1)}
+		queue := NewWebhookQueue(store,
+			WithWebhookQueueWorkers(2),
+			WithWebhookQueueLease(20*time.Millisecond),
+			WithWebhookQueuePollInterval(time.Millisecond),
+		)
+
+		release := make(chan struct{})
+		var calls atomic.Int32
+		runQueue(t, queue, func(ctx context.Context, event *WebhookEnvelope) error {
+			if calls.Add(1) == 1 {
+				// The first worker is still busy when its lease expires.
+				<-release
+				return errors.New("ledger unavailable")
+			}
+			return nil
+		})
+
+		require.NoError(t, queue.Enqueue(ctx, event("msg_1", WebhookEventPayoutComplete)))
+		status := func() QueuedWebhookStatus {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+			return store.deliveries["msg_1"].Status
+		}
+		require.Eventually(t, func() bool { return status() == QueuedWebhookCompleted }, 5*time.Second, time.Millisecond)
+
+		close(release)
+		select {
+		case err := <-store.retries:
+			require.ErrorIs(t, err, ErrWebhookClaimLost)
+		case <-time.After(5 * time.Second):
+			t.Fatal("retry not recorded")
+		}
+		require.Equal(t, QueuedWebhookCompleted, status())
+	})
+
+	t.Run("dead-letters undecodable deliveries", func(t *testing.T) {
+		store := NewMemoryWebhookQueueStore()
+		queue := NewWebhookQueue(store, WithWebhookQueuePollInterval(time.Millisecond))
+		runQueue(t, queue, func(ctx context.Context, event *WebhookEnvelope) error {
+			return nil
+		})
+
+		bad := event("msg_1", WebhookEventPayoutComplete)
+		bad.Raw = []byte(`{"id":1}`)
+		require.NoError(t, queue.Enqueue(ctx, bad))
+		require.Eventually(t, func() bool {
+			dead, err := queue.DeadLetters(ctx, DeadLetterFilter{})
+			return err == nil && len(dead) == 1 && dead[0].Attempts == 1
+		}, 5*time.Second, time.Millisecond)
+	})
+}
+
+// retryingQueueStore reports the errors returned by Retry.
+type retryingQueueStore struct {
+	*MemoryWebhookQueueStore
+	retries chan error
+}
+
+func (s *retryingQueueStore) Retry(ctx context.Context, id string, attempt int, next time.Time, lastError string) error {
+	err := s.MemoryWebhookQueueStore.Retry(ctx, id, attempt, next, lastError)
+	s.retries <- err
+	return err
+}
+
+func TestWebhookHandler_Queue(t *testing.T) {
+	secret := "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	payload := `{"webhook_event":"payout.complete","data":{"id":"pa_000000000000","status":"completed"}}`
+
+	store := NewMemoryWebhookQueueStore()
+	calls := 0
+	handler := NewWebhookHandler(secret,
+		WithWebhookQueue(NewWebhookQueue(store)),
+		OnPayoutComplete(func(ctx context.Context, payout *payouts.Payout) error {
+			calls++
+			return nil
+		}),
+	)
+
+	require.Equal(t, http.StatusAccepted, deliver(t, handler, secret, payload).Code)
+	require.Equal(t, http.StatusAccepted, deliver(t, handler, secret, payload).Code)
+	require.Zero(t, calls)
+	require.Len(t, store.deliveries, 1)
+	require.Equal(t, QueuedWebhookPending, store.deliveries["msg_000000000000"].Status)
+	require.Equal(t, WebhookEventPayoutComplete, store.deliveries["msg_000000000000"].Type)
+}
+
+func TestQueueBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 10 * time.Second, MaxDelay: time.Minute}
+
+	require.Equal(t, 10*time.Second, queueBackoff(policy, 1))
+	require.Equal(t, 40*time.Second, queueBackoff(policy, 3))
+	require.Equal(t, time.Minute, queueBackoff(policy, 10))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := queueBackoff(policy, 1)
+		require.GreaterOrEqual(t, delay, 5*time.Second)
+		require.LessOrEqual(t, delay, 10*time.Second)
+	}
+}
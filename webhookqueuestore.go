@@ -0,0 +1,397 @@
+package blindpay
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryWebhookQueueStore is a WebhookQueueStore keeping deliveries in memory.
+// Deliveries are lost when the process exits, so it suits tests and
+// development rather than production.
+type MemoryWebhookQueueStore struct {
+	mu         sync.Mutex
+	deliveries map[string]*QueuedWebhook
+	now        func() time.Time
+}
+
+// NewMemoryWebhookQueueStore returns an empty in-memory store.
+func NewMemoryWebhookQueueStore() *MemoryWebhookQueueStore {
+	return &MemoryWebhookQueueStore{
+		deliveries: make(map[string]*QueuedWebhook),
+		now:        time.Now,
+	}
+}
+
+// Enqueue implements WebhookQueueStore.
+func (s *MemoryWebhookQueueStore) Enqueue(_ context.Context, delivery *QueuedWebhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.deliveries[delivery.ID]; !ok {
+		stored := *delivery
+		s.deliveries[delivery.ID] = &stored
+	}
+	return nil
+}
+
+// Claim implements WebhookQueueStore.
+func (s *MemoryWebhookQueueStore) Claim(_ context.Context, now time.Time, lease time.Duration) (*QueuedWebhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var next *QueuedWebhook
+	for _, delivery := range s.deliveries {
+		due := delivery.Status == QueuedWebhookPending || delivery.Status == QueuedWebhookProcessing
+		if !due || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		if next == nil || delivery.NextAttemptAt.Before(next.NextAttemptAt) {
+			next = delivery
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	next.Status = QueuedWebhookProcessing
+	next.Attempts++
+	next.NextAttemptAt = now.Add(lease)
+	next.UpdatedAt = now
+	claimed := *next
+	return &claimed, nil
+}
+
+// Complete implements WebhookQueueStore.
+func (s *MemoryWebhookQueueStore) Complete(_ context.Context, id string, attempt int) error {
+	return s.update(id, attempt, func(delivery *QueuedWebhook) {
+		delivery.Status = QueuedWebhookCompleted
+	})
+}
+
+// Retry implements WebhookQueueStore.
+func (s *MemoryWebhookQueueStore) Retry(_ context.Context, id string, attempt int, next time.Time, lastError string) error {
+	return s.update(id, attempt, func(delivery *QueuedWebhook) {
+		delivery.Status = QueuedWebhookPending
+		delivery.NextAttemptAt = next
+		delivery.LastError = lastError
+	})
+}
+
+// DeadLetter implements WebhookQueueStore.
+func (s *MemoryWebhookQueueStore) DeadLetter(_ context.Context, id string, attempt int, lastError string) error {
+	return s.update(id, attempt, func(delivery *QueuedWebhook) {
+		delivery.Status = QueuedWebhookDead
+		delivery.LastError = lastError
+	})
+}
+
+// DeadLetters implements WebhookQueueStore.
+func (s *MemoryWebhookQueueStore) DeadLetters(_ context.Context, filter DeadLetterFilter) ([]QueuedWebhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var dead []QueuedWebhook
+	for _, delivery := range s.deliveries {
+		if delivery.Status != QueuedWebhookDead ||
+			(filter.Type != "" && delivery.Type != filter.Type) ||
+			delivery.UpdatedAt.Before(filter.Since) {
+			continue
+		}
+		dead = append(dead, *delivery)
+	}
+	sort.Slice(dead, func(i, j int) bool {
+		return dead[i].UpdatedAt.Before(dead[j].UpdatedAt)
+	})
+	if len(dead) > filter.limit() {
+		dead = dead[:filter.limit()]
+	}
+	return dead, nil
+}
+
+// Replay implements WebhookQueueStore.
+func (s *MemoryWebhookQueueStore) Replay(_ context.Context, id string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delivery, ok := s.deliveries[id]
+	if !ok || delivery.Status != QueuedWebhookDead {
+		return ErrDeadLetterNotFound
+	}
+	delivery.Status = QueuedWebhookPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = now
+	delivery.UpdatedAt = now
+	return nil
+}
+
+// Prune deletes the deliveries completed before the given time, and returns
+// how many were deleted.
+func (s *MemoryWebhookQueueStore) Prune(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+	for id, delivery := range s.deliveries {
+		if delivery.Status == QueuedWebhookCompleted && delivery.UpdatedAt.Before(before) {
+			delete(s.deliveries, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// update applies fn to the delivery id, or returns ErrWebhookClaimLost unless
+// it is processing under the given attempt.
+func (s *MemoryWebhookQueueStore) update(id string, attempt int, fn func(*QueuedWebhook)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delivery, ok := s.deliveries[id]
+	if !ok || delivery.Status != QueuedWebhookProcessing || delivery.Attempts != attempt {
+		return ErrWebhookClaimLost
+	}
+	fn(delivery)
+	delivery.UpdatedAt = s.now()
+	return nil
+}
+
+// queueColumns are the columns of a SQLWebhookQueueStore table, in the order
+// scanQueuedWebhook reads them.
+const queueColumns = "id, type, sent_at, payload, status, attempts, next_attempt_at, last_error, created_at, updated_at"
+
+// SQLWebhookQueueStore is a WebhookQueueStore backed by a database/sql table,
+// which lets several processes share the queue. The table is created by
+// CreateTable and holds one row per delivery, with times stored as Unix
+// milliseconds. An index on (status, next_attempt_at) keeps Claim fast on
+// large tables.
+type SQLWebhookQueueStore struct {
+	db          *sql.DB
+	table       string
+	placeholder SQLPlaceholder
+	now         func() time.Time
+}
+
+// NewSQLWebhookQueueStore returns a store using table in db, binding
+// parameters with placeholder.
+func NewSQLWebhookQueueStore(db *sql.DB, table string, placeholder SQLPlaceholder) (*SQLWebhookQueueStore, error) {
+	if db == nil {
+		return nil, errors.New("blindpay: nil database")
+	}
+	if !sqlIdentifier.MatchString(table) {
+		return nil, fmt.Errorf("blindpay: invalid table name %q", table)
+	}
+	return &SQLWebhookQueueStore{
+		db:          db,
+		table:       table,
+		placeholder: placeholder,
+		now:         time.Now,
+	}, nil
+}
+
+// CreateTable creates the table of the store if it does not exist.
+func (s *SQLWebhookQueueStore) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id VARCHAR(255) PRIMARY KEY,
+	type VARCHAR(64) NOT NULL,
+	sent_at BIGINT NOT NULL,
+	payload TEXT NOT NULL,
+	status VARCHAR(16) NOT NULL,
+	attempts INTEGER NOT NULL,
+	next_attempt_at BIGINT NOT NULL,
+	last_error TEXT NOT NULL,
+	created_at BIGINT NOT NULL,
+	updated_at BIGINT NOT NULL
+)`, s.table))
+	return err
+}
+
+// query formats a query of the store, in which %[1]s is the table and %[2]s
+// the list of its columns, and binds its parameters.
+func (s *SQLWebhookQueueStore) query(format string) string {
+	return s.placeholder.bind(fmt.Sprintf(format, s.table, queueColumns))
+}
+
+// Enqueue implements WebhookQueueStore. Like SQLDedupeStore.Claim, it falls
+// back to looking for the existing row when the insert fails.
+func (s *SQLWebhookQueueStore) Enqueue(ctx context.Context, delivery *QueuedWebhook) error {
+	_, insertErr := s.db.ExecContext(ctx, s.query("INSERT INTO %[1]s (%[2]s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"),
+		delivery.ID, string(delivery.Type), delivery.Timestamp.UnixMilli(), string(delivery.Payload),
+		string(delivery.Status), delivery.Attempts, delivery.NextAttemptAt.UnixMilli(), delivery.LastError,
+		delivery.CreatedAt.UnixMilli(), delivery.UpdatedAt.UnixMilli())
+	if insertErr == nil {
+		return nil
+	}
+
+	var exists int
+	err := s.db.QueryRowContext(ctx, s.query("SELECT 1 FROM %[1]s WHERE id = ?"), delivery.ID).Scan(&exists)
+	if err != nil {
+		return insertErr
+	}
+	return nil
+}
+
+// Claim implements WebhookQueueStore. It selects the next due row, then takes
+// it with an update conditioned on the row being unchanged, and looks for
+// another row when a concurrent claim took it first.
+func (s *SQLWebhookQueueStore) Claim(ctx context.Context, now time.Time, lease time.Duration) (*QueuedWebhook, error) {
+	for i := 0; i < 3; i++ {
+		rows, err := s.db.QueryContext(ctx, s.query(
+			"SELECT %[2]s FROM %[1]s WHERE status IN (?, ?) AND next_attempt_at <= ? ORDER BY next_attempt_at LIMIT 1"),
+			string(QueuedWebhookPending), string(QueuedWebhookProcessing), now.UnixMilli())
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim webhook: %w", err)
+		}
+		deliveries, err := scanQueuedWebhooks(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim webhook: %w", err)
+		}
+		if len(deliveries) == 0 {
+			return nil, nil
+		}
+		delivery := deliveries[0]
+
+		next := now.Add(lease)
+		res, err := s.db.ExecContext(ctx, s.query(
+			"UPDATE %[1]s SET status = ?, attempts = ?, next_attempt_at = ?, updated_at = ? WHERE id = ? AND status = ? AND next_attempt_at = ?"),
+			string(QueuedWebhookProcessing), delivery.Attempts+1, next.UnixMilli(), now.UnixMilli(),
+			delivery.ID, string(delivery.Status), delivery.NextAttemptAt.UnixMilli())
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim webhook %s: %w", delivery.ID, err)
+		}
+		if n, err := res.RowsAffected(); err != nil || n != 1 {
+			continue
+		}
+
+		delivery.Status = QueuedWebhookProcessing
+		delivery.Attempts++
+		delivery.NextAttemptAt = time.UnixMilli(next.UnixMilli())
+		delivery.UpdatedAt = time.UnixMilli(now.UnixMilli())
+		return &delivery, nil
+	}
+	return nil, nil
+}
+
+// Complete implements WebhookQueueStore.
+func (s *SQLWebhookQueueStore) Complete(ctx context.Context, id string, attempt int) error {
+	res, err := s.db.ExecContext(ctx, s.query("UPDATE %[1]s SET status = ?, updated_at = ? WHERE id = ? AND status = ? AND attempts = ?"),
+		string(QueuedWebhookCompleted), s.now().UnixMilli(), id, string(QueuedWebhookProcessing), attempt)
+	if err != nil {
+		return fmt.Errorf("failed to complete webhook %s: %w", id, err)
+	}
+	return claimHeld(res)
+}
+
+// Retry implements WebhookQueueStore.
+func (s *SQLWebhookQueueStore) Retry(ctx context.Context, id string, attempt int, next time.Time, lastError string) error {
+	res, err := s.db.ExecContext(ctx, s.query("UPDATE %[1]s SET status = ?, next_attempt_at = ?, last_error = ?, updated_at = ? WHERE id = ? AND status = ? AND attempts = ?"),
+		string(QueuedWebhookPending), next.UnixMilli(), lastError, s.now().UnixMilli(), id, string(QueuedWebhookProcessing), attempt)
+	if err != nil {
+		return fmt.Errorf("failed to retry webhook %s: %w", id, err)
+	}
+	return claimHeld(res)
+}
+
+// DeadLetter implements WebhookQueueStore.
+func (s *SQLWebhookQueueStore) DeadLetter(ctx context.Context, id string, attempt int, lastError string) error {
+	res, err := s.db.ExecContext(ctx, s.query("UPDATE %[1]s SET status = ?, last_error = ?, updated_at = ? WHERE id = ? AND status = ? AND attempts = ?"),
+		string(QueuedWebhookDead), lastError, s.now().UnixMilli(), id, string(QueuedWebhookProcessing), attempt)
+	if err != nil {
+		return fmt.Errorf("failed to dead-letter webhook %s: %w", id, err)
+	}
+	return claimHeld(res)
+}
+
+// claimHeld returns ErrWebhookClaimLost when the statement recording the
+// outcome of a claim matched no row.
+func claimHeld(res sql.Result) error {
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrWebhookClaimLost
+	}
+	return nil
+}
+
+// DeadLetters implements WebhookQueueStore.
+func (s *SQLWebhookQueueStore) DeadLetters(ctx context.Context, filter DeadLetterFilter) ([]QueuedWebhook, error) {
+	where := []string{"status = ?"}
+	args := []any{string(QueuedWebhookDead)}
+	if filter.Type != "" {
+		where = append(where, "type = ?")
+		args = append(args, string(filter.Type))
+	}
+	if !filter.Since.IsZero() {
+		where = append(where, "updated_at >= ?")
+		args = append(args, filter.Since.UnixMilli())
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.query(
+		"SELECT %[2]s FROM %[1]s WHERE "+strings.Join(where, " AND ")+" ORDER BY updated_at LIMIT "+strconv.Itoa(filter.limit())),
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead-lettered webhooks: %w", err)
+	}
+	deliveries, err := scanQueuedWebhooks(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead-lettered webhooks: %w", err)
+	}
+	return deliveries, nil
+}
+
+// Replay implements WebhookQueueStore.
+func (s *SQLWebhookQueueStore) Replay(ctx context.Context, id string, now time.Time) error {
+	res, err := s.db.ExecContext(ctx, s.query("UPDATE %[1]s SET status = ?, attempts = ?, next_attempt_at = ?, updated_at = ? WHERE id = ? AND status = ?"),
+		string(QueuedWebhookPending), 0, now.UnixMilli(), now.UnixMilli(), id, string(QueuedWebhookDead))
+	if err != nil {
+		return fmt.Errorf("failed to replay webhook %s: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrDeadLetterNotFound
+	}
+	return nil
+}
+
+// Prune deletes the deliveries completed before the given time, and returns
+// how many were deleted. Completed rows only serve to ignore redeliveries, which
+// Svix stops after a few days.
+func (s *SQLWebhookQueueStore) Prune(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, s.query("DELETE FROM %[1]s WHERE status = ? AND updated_at < ?"),
+		string(QueuedWebhookCompleted), before.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune webhooks: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// scanQueuedWebhooks reads rows selecting queueColumns, and closes them.
+func scanQueuedWebhooks(rows *sql.Rows) ([]QueuedWebhook, error) {
+	defer rows.Close()
+
+	var deliveries []QueuedWebhook
+	for rows.Next() {
+		var (
+			d                                         QueuedWebhook
+			eventType, payload, status                string
+			sentAt, nextAttemptAt, createdAt, updated int64
+		)
+		err := rows.Scan(&d.ID, &eventType, &sentAt, &payload, &status, &d.Attempts,
+			&nextAttemptAt, &d.LastError, &createdAt, &updated)
+		if err != nil {
+			return nil, err
+		}
+		d.Type = WebhookEvent(eventType)
+		d.Payload = []byte(payload)
+		d.Status = QueuedWebhookStatus(status)
+		d.Timestamp = time.UnixMilli(sentAt).UTC()
+		d.NextAttemptAt = time.UnixMilli(nextAttemptAt)
+		d.CreatedAt = time.UnixMilli(createdAt)
+		d.UpdatedAt = time.UnixMilli(updated)
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
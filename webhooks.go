@@ -1,9 +1,205 @@
 package blindpay
 
 import (
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
 	svix "github.com/svix/svix-webhooks/go"
+
+	"github.com/blindpaylabs/blindpay-go/internal/request"
+)
+
+// Reasons for which VerifyWebhook rejects a delivery, matched by errors.Is
+// against a *WebhookVerificationError:
+//
+//	if errors.Is(err, blindpay.ErrWebhookTimestamp) {
+//		// ...
+//	}
+var (
+	// ErrWebhookSecret means a secret is not a valid signing secret, or that
+	// no secret was given. It is a configuration error rather than a bad
+	// delivery.
+	ErrWebhookSecret = errors.New("invalid webhook secret")
+	// ErrWebhookMissingHeader means the webhook-id, webhook-timestamp or
+	// webhook-signature header is missing.
+	ErrWebhookMissingHeader = errors.New("missing webhook header")
+	// ErrWebhookTimestamp means the webhook-timestamp is malformed, or too far
+	// from now for the delivery not to be a replay.
+	ErrWebhookTimestamp = errors.New("invalid webhook timestamp")
+	// ErrWebhookSignatureMismatch means no signature of the delivery matches
+	// any of the secrets: the body was tampered with, or it was signed with
+	// another secret.
+	ErrWebhookSignatureMismatch = errors.New("webhook signature mismatch")
 )
 
+// WebhookVerificationError reports why a webhook delivery was rejected. It
+// matches its Reason, and every reason other than ErrWebhookSecret also
+// matches ErrInvalidWebhookSignature.
+type WebhookVerificationError struct {
+	// Reason is one of ErrWebhookSecret, ErrWebhookMissingHeader,
+	// ErrWebhookTimestamp and ErrWebhookSignatureMismatch.
+	Reason error
+	// Detail describes the offending value, such as the missing header.
+	Detail string
+}
+
+// Error implements the error interface for WebhookVerificationError.
+func (e *WebhookVerificationError) Error() string {
+	if e.Detail == "" {
+		return "blindpay: " + e.Reason.Error()
+	}
+	return "blindpay: " + e.Reason.Error() + ": " + e.Detail
+}
+
+// Unwrap returns the reason.
+func (e *WebhookVerificationError) Unwrap() error {
+	return e.Reason
+}
+
+// Is makes errors.Is(err, ErrInvalidWebhookSignature) match a
+// WebhookVerificationError caused by the delivery.
+func (e *WebhookVerificationError) Is(target error) bool {
+	return target == ErrInvalidWebhookSignature && e.Reason != ErrWebhookSecret
+}
+
+// VerifyWebhook verifies the Svix signature of a webhook delivery, and returns
+// a *WebhookVerificationError telling why it is rejected.
+//
+// header holds the headers of the delivery and body is the raw request body.
+// The delivery is accepted when it is signed with any of secrets, so that
+// while a secret is being rotated both the old and the new secret returned by
+// webhookendpoints.Client.GetSecret can be given. Its webhook-timestamp must
+// be within DefaultWebhookTolerance of now.
+func VerifyWebhook(header http.Header, body []byte, secrets ...string) error {
+	return VerifyWebhookWithTolerance(header, body, DefaultWebhookTolerance, secrets...)
+}
+
+// VerifyWebhookWithTolerance is VerifyWebhook accepting deliveries whose
+// webhook-timestamp is within tolerance of now. A non-positive tolerance
+// stands for DefaultWebhookTolerance.
+func VerifyWebhookWithTolerance(header http.Header, body []byte, tolerance time.Duration, secrets ...string) error {
+	if tolerance <= 0 {
+		tolerance = DefaultWebhookTolerance
+	}
+	return verifyWebhook(header, body, secrets, tolerance, time.Now())
+}
+
+// SignWebhook signs payload with secret the way Svix signs BlindPay
+// deliveries, and returns the webhook-id, webhook-timestamp and
+// webhook-signature headers that VerifyWebhook accepts. An empty id is
+// replaced by a random one and a zero timestamp by the current time.
+//
+// It is meant for tests and local development, to send handlers deliveries
+// that are signed for real.
+func SignWebhook(secret, id string, timestamp time.Time, payload []byte) (http.Header, error) {
+	wh, err := svix.NewWebhook(secret)
+	if err != nil {
+		return nil, &WebhookVerificationError{Reason: ErrWebhookSecret, Detail: err.Error()}
+	}
+	if id == "" {
+		id = "msg_" + strings.ReplaceAll(request.NewIdempotencyKey(), "-", "")
+	}
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	signature, err := wh.Sign(id, timestamp, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign webhook: %w", err)
+	}
+
+	header := http.Header{}
+	header.Set("Webhook-Id", id)
+	header.Set("Webhook-Timestamp", strconv.FormatInt(timestamp.Unix(), 10))
+	header.Set("Webhook-Signature", signature)
+	return header, nil
+}
+
+// verifyWebhook is VerifyWebhook with a configurable timestamp tolerance.
+func verifyWebhook(header http.Header, body []byte, secrets []string, tolerance time.Duration, now time.Time) error {
+	if len(secrets) == 0 {
+		return &WebhookVerificationError{Reason: ErrWebhookSecret, Detail: "no secret given"}
+	}
+	hooks := make([]*svix.Webhook, len(secrets))
+	for i, secret := range secrets {
+		wh, err := svix.NewWebhook(secret)
+		if err != nil {
+			return &WebhookVerificationError{Reason: ErrWebhookSecret, Detail: fmt.Sprintf("secret %d: %v", i, err)}
+		}
+		hooks[i] = wh
+	}
+
+	id, timestamp, signatures, err := webhookHeaders(header)
+	if err != nil {
+		return err
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return &WebhookVerificationError{Reason: ErrWebhookTimestamp, Detail: fmt.Sprintf("%q is not a Unix time", timestamp)}
+	}
+	sent := time.Unix(seconds, 0)
+
+	// The signature is checked before the timestamp, so that a stale
+	// timestamp error is only reported for a genuine delivery.
+	if !webhookSignatureMatches(hooks, id, sent, body, signatures) {
+		return &WebhookVerificationError{Reason: ErrWebhookSignatureMismatch}
+	}
+
+	if age := now.Sub(sent); age > tolerance || age < -tolerance {
+		return &WebhookVerificationError{
+			Reason: ErrWebhookTimestamp,
+			Detail: fmt.Sprintf("sent %s away from now, beyond the %s tolerance", age.Truncate(time.Second), tolerance),
+		}
+	}
+	return nil
+}
+
+// webhookHeaders returns the webhook-id, webhook-timestamp and
+// webhook-signature headers, or their svix- prefixed equivalents.
+func webhookHeaders(header http.Header) (id, timestamp, signatures string, err error) {
+	id, timestamp, signatures = header.Get("Webhook-Id"), header.Get("Webhook-Timestamp"), header.Get("Webhook-Signature")
+	if id == "" && timestamp == "" && signatures == "" {
+		id, timestamp, signatures = header.Get("Svix-Id"), header.Get("Svix-Timestamp"), header.Get("Svix-Signature")
+	}
+
+	var missing []string
+	if id == "" {
+		missing = append(missing, "webhook-id")
+	}
+	if timestamp == "" {
+		missing = append(missing, "webhook-timestamp")
+	}
+	if signatures == "" {
+		missing = append(missing, "webhook-signature")
+	}
+	if len(missing) > 0 {
+		return "", "", "", &WebhookVerificationError{Reason: ErrWebhookMissingHeader, Detail: strings.Join(missing, ", ")}
+	}
+	return id, timestamp, signatures, nil
+}
+
+// webhookSignatureMatches reports whether one of the space separated v1
+// signatures matches the delivery signed with one of hooks.
+func webhookSignatureMatches(hooks []*svix.Webhook, id string, sent time.Time, body []byte, signatures string) bool {
+	for _, wh := range hooks {
+		expected, err := wh.Sign(id, sent, body)
+		if err != nil {
+			continue
+		}
+		for _, signature := range strings.Fields(signatures) {
+			if strings.HasPrefix(signature, "v1,") && hmac.Equal([]byte(signature), []byte(expected)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // VerifyWebhookSignature verifies the BlindPay webhook signature using Svix.
 //
 // Parameters:
@@ -13,20 +209,19 @@ import (
 //   - payload: The raw request body as a string
 //   - signature: The value of the `webhook-signature` header
 //
-// Returns true if the signature is valid, false otherwise.
+// Returns true if the signature is valid, false otherwise. Use VerifyWebhook
+// to know why a delivery is rejected.
 func VerifyWebhookSignature(secret, id, timestamp, payload, signature string) bool {
-	wh, err := svix.NewWebhook(secret)
-	if err != nil {
-		return false
-	}
+	return VerifyWebhook(webhookSignatureHeader(id, timestamp, signature), []byte(payload), secret) == nil
+}
 
+// webhookSignatureHeader returns the headers of a delivery with the given
+// webhook-id, webhook-timestamp and webhook-signature.
+func webhookSignatureHeader(id, timestamp, signature string) http.Header {
 	// Keys must use canonical HTTP header casing
-	headers := map[string][]string{
+	return http.Header{
 		"Webhook-Id":        {id},
 		"Webhook-Timestamp": {timestamp},
 		"Webhook-Signature": {signature},
 	}
-
-	err = wh.Verify([]byte(payload), headers)
-	return err == nil
 }
@@ -1,8 +1,9 @@
 package blindpay
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,16 +12,12 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// generateTestSignature creates a valid Svix signature for testing
-func generateTestSignature(t *testing.T, secret, id, payload string) (timestamp, signature string) {
-	wh, err := svix.NewWebhook(secret)
-	require.NoError(t, err)
-
-	ts := time.Now()
-	sig, err := wh.Sign(id, ts, []byte(payload))
+// signedHeader returns the Svix headers of a delivery of payload sent at the
+// given time, or now when sent is zero.
+func signedHeader(t *testing.T, secret, id string, sent time.Time, payload string) http.Header {
+	header, err := SignWebhook(secret, id, sent, []byte(payload))
 	require.NoError(t, err)
-
-	return fmt.Sprintf("%d", ts.Unix()), sig
+	return header
 }
 
 func TestVerifyWebhookSignature(t *testing.T) {
@@ -32,7 +29,8 @@ func TestVerifyWebhookSignature(t *testing.T) {
 	id := "msg_p5jXN8AQM9LWM0D4loKWxJek"
 
 	// Generate valid signature with current timestamp
-	timestamp, signature := generateTestSignature(t, secret, id, payload)
+	header := signedHeader(t, secret, id, time.Time{}, payload)
+	timestamp, signature := header.Get("Webhook-Timestamp"), header.Get("Webhook-Signature")
 
 	tests := []struct {
 		name          string
@@ -149,7 +147,8 @@ func TestVerifyWebhookSignature_RealWorldExample(t *testing.T) {
 }`
 
 	// Generate signature with current timestamp
-	timestamp, signature := generateTestSignature(t, secret, id, payload)
+	header := signedHeader(t, secret, id, time.Time{}, payload)
+	timestamp, signature := header.Get("Webhook-Timestamp"), header.Get("Webhook-Signature")
 
 	valid := VerifyWebhookSignature(secret, id, timestamp, payload, signature)
 	require.True(t, valid, "Real-world webhook signature should be valid")
@@ -176,7 +175,8 @@ func TestVerifyWebhookSignature_TimingAttackResistance(t *testing.T) {
 	payload := `{"test":"data"}`
 
 	// Generate correct signature with current timestamp
-	timestamp, correctSignature := generateTestSignature(t, secret, id, payload)
+	header := signedHeader(t, secret, id, time.Time{}, payload)
+	timestamp, correctSignature := header.Get("Webhook-Timestamp"), header.Get("Webhook-Signature")
 
 	// Test correct signature first
 	valid := VerifyWebhookSignature(secret, id, timestamp, payload, correctSignature)
@@ -200,7 +200,8 @@ func TestVerifyWebhookSignature_WithHTTPRequest(t *testing.T) {
 	payload := `{"webhook_event":"payment.completed","amount":1000}`
 
 	// Generate signature with current timestamp
-	timestamp, signature := generateTestSignature(t, secret, id, payload)
+	header := signedHeader(t, secret, id, time.Time{}, payload)
+	timestamp, signature := header.Get("Webhook-Timestamp"), header.Get("Webhook-Signature")
 
 	// Simulate extracting headers from HTTP request
 	req, _ := http.NewRequest("POST", "/webhook", nil)
@@ -218,3 +219,131 @@ func TestVerifyWebhookSignature_WithHTTPRequest(t *testing.T) {
 	)
 	require.True(t, valid, "Webhook signature from HTTP request should be valid")
 }
+
+func TestVerifyWebhook(t *testing.T) {
+	oldSecret := "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	newSecret := "whsec_dGhpcyBpcyBhbm90aGVyIHNlY3JldA=="
+	payload := `{"webhook_event":"payout.complete","data":{"id":"pa_000000000000"}}`
+	id := "msg_p5jXN8AQM9LWM0D4loKWxJek"
+
+	signed := func(secret string, sent time.Time) http.Header {
+		return signedHeader(t, secret, id, sent, payload)
+	}
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		body    string
+		secrets []string
+		reason  error
+	}{
+		{
+			name:    "valid",
+			header:  signed(oldSecret, time.Now()),
+			body:    payload,
+			secrets: []string{oldSecret},
+		},
+		{
+			name:    "signed with the new secret during rotation",
+			header:  signed(newSecret, time.Now()),
+			body:    payload,
+			secrets: []string{oldSecret, newSecret},
+		},
+		{
+			name:    "signed with another secret",
+			header:  signed(newSecret, time.Now()),
+			body:    payload,
+			secrets: []string{oldSecret},
+			reason:  ErrWebhookSignatureMismatch,
+		},
+		{
+			name:    "tampered body",
+			header:  signed(oldSecret, time.Now()),
+			body:    `{"webhook_event":"payout.complete","data":{"id":"pa_111111111111"}}`,
+			secrets: []string{oldSecret},
+			reason:  ErrWebhookSignatureMismatch,
+		},
+		{
+			name:    "stale timestamp",
+			header:  signed(oldSecret, time.Now().Add(-time.Hour)),
+			body:    payload,
+			secrets: []string{oldSecret},
+			reason:  ErrWebhookTimestamp,
+		},
+		{
+			name:    "missing header",
+			header:  http.Header{"Webhook-Id": {id}},
+			body:    payload,
+			secrets: []string{oldSecret},
+			reason:  ErrWebhookMissingHeader,
+		},
+		{
+			name:    "bad secret",
+			header:  signed(oldSecret, time.Now()),
+			body:    payload,
+			secrets: []string{oldSecret, "whsec_not base64!"},
+			reason:  ErrWebhookSecret,
+		},
+		{
+			name:   "no secret",
+			header: signed(oldSecret, time.Now()),
+			body:   payload,
+			reason: ErrWebhookSecret,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyWebhook(tt.header, []byte(tt.body), tt.secrets...)
+			if tt.reason == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var verr *WebhookVerificationError
+			require.ErrorAs(t, err, &verr)
+			require.ErrorIs(t, err, tt.reason)
+			require.Equal(t, tt.reason != ErrWebhookSecret, errors.Is(err, ErrInvalidWebhookSignature))
+		})
+	}
+}
+
+func TestVerifyWebhookWithTolerance(t *testing.T) {
+	secret := "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	payload := `{"webhook_event":"payout.complete","data":{"id":"pa_000000000000"}}`
+
+	header := signedHeader(t, secret, "", time.Now().Add(-time.Hour), payload)
+
+	require.ErrorIs(t, VerifyWebhook(header, []byte(payload), secret), ErrWebhookTimestamp)
+	require.ErrorIs(t, VerifyWebhookWithTolerance(header, []byte(payload), 0, secret), ErrWebhookTimestamp)
+	require.NoError(t, VerifyWebhookWithTolerance(header, []byte(payload), 2*time.Hour, secret))
+
+	_, err := ParseWebhookEvent(secret, header, []byte(payload))
+	require.ErrorIs(t, err, ErrWebhookTimestamp)
+	event, err := ParseWebhookEventWithTolerance(secret, header, []byte(payload), 2*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, WebhookEventPayoutComplete, event.Type)
+}
+
+func TestSignWebhook(t *testing.T) {
+	secret := "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	payload := []byte(`{"webhook_event":"payout.update","data":{"id":"pa_000000000000"}}`)
+
+	header, err := SignWebhook(secret, "", time.Time{}, payload)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(header.Get("Webhook-Id"), "msg_"))
+	require.NoError(t, VerifyWebhook(header, payload, secret))
+
+	sent := time.Unix(1704067200, 0)
+	header, err = SignWebhook(secret, "msg_p5jXN8AQM9LWM0D4loKWxJek", sent, payload)
+	require.NoError(t, err)
+	require.Equal(t, "1704067200", header.Get("Webhook-Timestamp"))
+	wh, err := svix.NewWebhook(secret)
+	require.NoError(t, err)
+	signature, err := wh.Sign("msg_p5jXN8AQM9LWM0D4loKWxJek", sent, payload)
+	require.NoError(t, err)
+	require.Equal(t, signature, header.Get("Webhook-Signature"), "deliveries are signed the way Svix signs them")
+
+	_, err = SignWebhook("whsec_not base64!", "", time.Time{}, payload)
+	require.ErrorIs(t, err, ErrWebhookSecret)
+}
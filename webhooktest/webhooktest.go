@@ -0,0 +1,453 @@
+// Package webhooktest builds signed BlindPay webhook deliveries, for testing
+// webhook handlers and exercising them during local development.
+//
+//	req, err := webhooktest.NewRequest(srv.URL+"/webhooks", secret, blindpay.WebhookEventPayoutUpdate, nil)
+//	if err != nil {
+//		// ...
+//	}
+//	resp, err := http.DefaultClient.Do(req)
+package webhooktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	blindpay "github.com/blindpaylabs/blindpay-go"
+	"github.com/blindpaylabs/blindpay-go/bankaccounts"
+	"github.com/blindpaylabs/blindpay-go/custodialwallets"
+	"github.com/blindpaylabs/blindpay-go/internal/types"
+	"github.com/blindpaylabs/blindpay-go/payins"
+	"github.com/blindpaylabs/blindpay-go/payouts"
+	"github.com/blindpaylabs/blindpay-go/receivers"
+	"github.com/blindpaylabs/blindpay-go/transfers"
+	"github.com/blindpaylabs/blindpay-go/virtualaccounts"
+	"github.com/blindpaylabs/blindpay-go/wallets"
+)
+
+// Example IDs shared by the events, so that related events refer to each other.
+const (
+	InstanceID         = "in_000000000000"
+	ReceiverID         = "re_Yx2mP7dQk9Lw"
+	BankAccountID      = "ba_Hj4nR8sVt2Kc"
+	BlockchainWalletID = "bw_Fq6zC1xNb5Mp"
+	PayoutID           = "pa_Ks8vD3wLq7Tz"
+	PayinID            = "pi_Wn5tG2hYr9Bx"
+	TransferID         = "tr_Zc3kJ6mPv1Qd"
+	VirtualAccountID   = "va_Lp9sX4fRn8Gh"
+	CustodialWalletID  = "cw_Tb7qM2yKd5Vj"
+)
+
+// Time is when the example events happened.
+var Time = time.Date(2025, time.January, 15, 14, 30, 0, 0, time.UTC)
+
+// Events returns every webhook event type, in the order they are declared.
+func Events() []types.WebhookEvent {
+	return []types.WebhookEvent{
+		types.WebhookEventReceiverNew,
+		types.WebhookEventReceiverUpdate,
+		types.WebhookEventBankAccountNew,
+		types.WebhookEventPayoutNew,
+		types.WebhookEventPayoutUpdate,
+		types.WebhookEventPayoutComplete,
+		types.WebhookEventPayoutPartnerFee,
+		types.WebhookEventBlockchainWalletNew,
+		types.WebhookEventPayinNew,
+		types.WebhookEventPayinUpdate,
+		types.WebhookEventPayinComplete,
+		types.WebhookEventPayinPartnerFee,
+		types.WebhookEventTosAccept,
+		types.WebhookEventLimitIncreaseNew,
+		types.WebhookEventLimitIncreaseUpdate,
+		types.WebhookEventVirtualAccountNew,
+		types.WebhookEventVirtualAccountComplete,
+		types.WebhookEventTransferNew,
+		types.WebhookEventTransferUpdate,
+		types.WebhookEventTransferComplete,
+		types.WebhookEventWalletNew,
+		types.WebhookEventWalletInbound,
+	}
+}
+
+// Example returns the payload of an example event, as a pointer to its SDK
+// model that callers may change before passing it to Payload or NewRequest.
+// It is the model blindpay.ParseWebhookEvent decodes the event into, and a
+// map[string]any for tos.accept and wallet.inbound, which have no model.
+func Example(event types.WebhookEvent) (any, error) {
+	switch event {
+	case types.WebhookEventReceiverNew:
+		r := receiver()
+		r.KycStatus = "verifying"
+		return r, nil
+	case types.WebhookEventReceiverUpdate:
+		r := receiver()
+		r.KycStatus = "approved"
+		r.UpdatedAt = Time.Add(2 * time.Hour)
+		return r, nil
+	case types.WebhookEventBankAccountNew:
+		return &bankaccounts.BankAccount{
+			ID:              BankAccountID,
+			Type:            types.RailPix,
+			Name:            "Maria's Pix",
+			PixKey:          "maria.souza@example.com",
+			BeneficiaryName: "Maria Souza",
+			Country:         types.CountryBR,
+		}, nil
+	case types.WebhookEventPayoutNew:
+		p := payout()
+		p.Status = types.TransactionStatusProcessing
+		p.TrackingTransaction = nil
+		return p, nil
+	case types.WebhookEventPayoutUpdate:
+		p := payout()
+		p.Status = types.TransactionStatusProcessing
+		p.TrackingPayment = &types.TrackingPayment{
+			Step:                   "processing",
+			ProviderName:           "blindpay",
+			EstimatedTimeOfArrival: "5_min",
+		}
+		p.UpdatedAt = Time.Add(time.Minute)
+		return p, nil
+	case types.WebhookEventPayoutComplete:
+		return completedPayout(), nil
+	case types.WebhookEventPayoutPartnerFee:
+		p := completedPayout()
+		p.PartnerFeeAmount = 50
+		p.TrackingPartnerFee = &types.TrackingPartnerFee{
+			Step:            "completed",
+			TransactionHash: "0x5d1c2b7e9f0a3c4d6e8f1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d",
+			CompletedAt:     Time.Add(6 * time.Minute),
+		}
+		return p, nil
+	case types.WebhookEventBlockchainWalletNew:
+		return &wallets.BlockchainWallet{
+			ID:         BlockchainWalletID,
+			Name:       "Treasury",
+			Network:    types.NetworkBase,
+			Address:    "0x8ba1f109551bD432803012645Ac136ddd64DBA72",
+			ReceiverID: ReceiverID,
+		}, nil
+	case types.WebhookEventPayinNew:
+		p := payin()
+		p.Status = types.TransactionStatusPending
+		return p, nil
+	case types.WebhookEventPayinUpdate:
+		p := payin()
+		p.Status = types.TransactionStatusProcessing
+		p.TrackingTransaction = &types.TrackingTransaction{
+			Step:        "completed",
+			Status:      "received",
+			CompletedAt: Time.Add(time.Minute),
+		}
+		p.UpdatedAt = Time.Add(time.Minute)
+		return p, nil
+	case types.WebhookEventPayinComplete:
+		return completedPayin(), nil
+	case types.WebhookEventPayinPartnerFee:
+		p := completedPayin()
+		p.PartnerFeeAmount = 50
+		p.TrackingPartnerFee = &types.TrackingPartnerFee{
+			Step:            "completed",
+			TransactionHash: "0x9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b",
+			CompletedAt:     Time.Add(4 * time.Minute),
+		}
+		return p, nil
+	case types.WebhookEventTosAccept:
+		return map[string]any{
+			"id":          "to_Rm3wK8cVx6Ns",
+			"instance_id": InstanceID,
+			"receiver_id": ReceiverID,
+			"accepted_at": Time,
+		}, nil
+	case types.WebhookEventLimitIncreaseNew:
+		return limitIncrease(receivers.LimitIncreaseRequestStatusInReview), nil
+	case types.WebhookEventLimitIncreaseUpdate:
+		return limitIncrease(receivers.LimitIncreaseRequestStatusApproved), nil
+	case types.WebhookEventVirtualAccountNew:
+		return virtualAccount("pending"), nil
+	case types.WebhookEventVirtualAccountComplete:
+		return virtualAccount("approved"), nil
+	case types.WebhookEventTransferNew:
+		t := transfer()
+		t.Status = types.TransactionStatusProcessing
+		return t, nil
+	case types.WebhookEventTransferUpdate:
+		t := transfer()
+		t.Status = types.TransactionStatusProcessing
+		t.TrackingPaymaster = transferStep("completed", Time.Add(time.Minute))
+		t.UpdatedAt = Time.Add(time.Minute)
+		return t, nil
+	case types.WebhookEventTransferComplete:
+		t := transfer()
+		t.Status = types.TransactionStatusCompleted
+		t.TrackingPaymaster = transferStep("completed", Time.Add(time.Minute))
+		t.TrackingBridgeSwap = transferStep("completed", Time.Add(2*time.Minute))
+		t.TrackingComplete = transferStep("completed", Time.Add(3*time.Minute))
+		t.UpdatedAt = Time.Add(3 * time.Minute)
+		return t, nil
+	case types.WebhookEventWalletNew:
+		address := "0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984"
+		return &custodialwallets.CustodialWallet{
+			ID:        CustodialWalletID,
+			Name:      "Operating",
+			Address:   &address,
+			Network:   types.NetworkBase,
+			CreatedAt: Time,
+		}, nil
+	case types.WebhookEventWalletInbound:
+		return map[string]any{
+			"id":               "wi_Dn4xB9rLt2Hy",
+			"wallet_id":        CustodialWalletID,
+			"receiver_id":      ReceiverID,
+			"network":          types.NetworkBase,
+			"token":            types.StablecoinTokenUSDC,
+			"amount":           250000,
+			"from_address":     "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+			"transaction_hash": "0x3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f",
+			"created_at":       Time,
+		}, nil
+	}
+	return nil, fmt.Errorf("webhooktest: no example for webhook event %q", event)
+}
+
+// Payload returns the body of a delivery of event holding data, with the
+// "webhook_event" field set to event. A nil data uses the Example of event.
+func Payload(event types.WebhookEvent, data any) ([]byte, error) {
+	if data == nil {
+		var err error
+		if data, err = Example(event); err != nil {
+			return nil, err
+		}
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("webhooktest: failed to encode %s payload: %w", event, err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("webhooktest: %s payload is not a JSON object: %w", event, err)
+	}
+	fields["webhook_event"], _ = json.Marshal(event)
+
+	return json.Marshal(fields)
+}
+
+// NewRequest returns a POST request to url delivering event with data, signed
+// with secret at the current time. A nil data uses the Example of event.
+func NewRequest(url, secret string, event types.WebhookEvent, data any) (*http.Request, error) {
+	payload, err := Payload(event, data)
+	if err != nil {
+		return nil, err
+	}
+	header, err := blindpay.SignWebhook(secret, "", time.Time{}, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+func receiver() *receivers.Receiver {
+	return &receivers.Receiver{
+		ID:                  ReceiverID,
+		Type:                types.AccountClassIndividual,
+		KycType:             receivers.KycTypeStandard,
+		Email:               "maria.souza@example.com",
+		TaxID:               "12345678901",
+		AddressLine1:        "Av. Paulista, 1578",
+		City:                "São Paulo",
+		StateProvinceRegion: "SP",
+		Country:             types.CountryBR,
+		PostalCode:          "01310-200",
+		InstanceID:          InstanceID,
+		CreatedAt:           Time,
+		UpdatedAt:           Time,
+		Limit:               receivers.Limits{PerTransaction: 100000, Daily: 200000, Monthly: 1000000},
+		FirstName:           "Maria",
+		LastName:            "Souza",
+		DateOfBirth:         "1990-04-12",
+		IDDocCountry:        types.CountryBR,
+	}
+}
+
+func payout() *payouts.Payout {
+	return &payouts.Payout{
+		ID:                  PayoutID,
+		ReceiverID:          ReceiverID,
+		InstanceID:          InstanceID,
+		QuoteID:             "qu_Gv5rN1kWs8Pc",
+		SenderWalletAddress: "0x8ba1f109551bD432803012645Ac136ddd64DBA72",
+		TrackingTransaction: &types.TrackingTransaction{
+			Step:            "completed",
+			Status:          "found",
+			TransactionHash: "0x2c4e6a8b0d1f3a5c7e9b1d3f5a7c9e1b3d5f7a9c1e3b5d7f9a1c3e5b7d9f1a3c",
+			CompletedAt:     Time.Add(30 * time.Second),
+		},
+		CreatedAt:           Time,
+		UpdatedAt:           Time,
+		FirstName:           "Maria",
+		LastName:            "Souza",
+		Network:             types.NetworkBase,
+		Token:               types.StablecoinTokenUSDC,
+		Description:         "Invoice 2025-0142",
+		SenderAmount:        100000,
+		ReceiverAmount:      98500,
+		TotalFeeAmount:      1500,
+		CommercialQuotation: 6.07,
+		BlindpayQuotation:   5.98,
+		ReceiverLocalAmount: 589030,
+		Currency:            types.CurrencyBRL,
+		Name:                "Maria's Pix",
+		Type:                types.RailPix,
+		PixKey:              "maria.souza@example.com",
+		Country:             types.CountryBR,
+	}
+}
+
+func completedPayout() *payouts.Payout {
+	p := payout()
+	p.Status = types.TransactionStatusCompleted
+	p.TrackingPayment = &types.TrackingPayment{
+		Step:                  "completed",
+		ProviderName:          "blindpay",
+		ProviderTransactionID: "E18236120202501151430s0000000001",
+		ProviderStatus:        "completed",
+		CompletedAt:           Time.Add(4 * time.Minute),
+	}
+	p.TrackingComplete = &types.TrackingComplete{
+		Step:        "completed",
+		Status:      "completed",
+		CompletedAt: Time.Add(5 * time.Minute),
+	}
+	p.UpdatedAt = Time.Add(5 * time.Minute)
+	return p
+}
+
+func payin() *payins.Payin {
+	return &payins.Payin{
+		ID:                  PayinID,
+		ReceiverID:          ReceiverID,
+		InstanceID:          InstanceID,
+		PayinQuoteID:        "pq_Xw2cH7nMs4Lk",
+		PixCode:             "00020126580014br.gov.bcb.pix0136a1b2c3d4-e5f6-7a8b-9c0d-e1f2a3b4c5d6520400005303986540510.005802BR6304ABCD",
+		CreatedAt:           Time,
+		UpdatedAt:           Time,
+		FirstName:           "Maria",
+		LastName:            "Souza",
+		Type:                "individual",
+		PaymentMethod:       "pix",
+		SenderAmount:        600000,
+		ReceiverAmount:      98800,
+		Token:               types.StablecoinTokenUSDC,
+		TotalFeeAmount:      1200,
+		CommercialQuotation: 6.07,
+		BlindpayQuotation:   6.01,
+		Currency:            string(types.CurrencyBRL),
+		Name:                "Treasury",
+		Address:             "0x8ba1f109551bD432803012645Ac136ddd64DBA72",
+		Network:             types.NetworkBase,
+	}
+}
+
+func completedPayin() *payins.Payin {
+	p := payin()
+	p.Status = types.TransactionStatusCompleted
+	p.TrackingTransaction = &types.TrackingTransaction{
+		Step:        "completed",
+		Status:      "received",
+		CompletedAt: Time.Add(time.Minute),
+	}
+	p.TrackingComplete = &types.TrackingComplete{
+		Step:            "completed",
+		Status:          "completed",
+		TransactionHash: "0x7b9d1f3a5c7e9b1d3f5a7c9e1b3d5f7a9c1e3b5d7f9a1c3e5b7d9f1a3c5e7b9d",
+		CompletedAt:     Time.Add(3 * time.Minute),
+	}
+	p.UpdatedAt = Time.Add(3 * time.Minute)
+	return p
+}
+
+func limitIncrease(status receivers.LimitIncreaseRequestStatus) *receivers.LimitIncreaseRequest {
+	return &receivers.LimitIncreaseRequest{
+		ID:                     "rl_Qj8tW3vBn6Cz",
+		ReceiverID:             ReceiverID,
+		Status:                 status,
+		Daily:                  500000,
+		Monthly:                2500000,
+		PerTransaction:         250000,
+		SupportingDocumentFile: "https://example.com/documents/bank-statement.pdf",
+		SupportingDocumentType: receivers.LimitIncreaseRequestSupportingDocumentTypeIndividualBankStatement,
+		CreatedAt:              Time.Format(time.RFC3339),
+		UpdatedAt:              Time.Format(time.RFC3339),
+	}
+}
+
+func virtualAccount(kycStatus string) *virtualaccounts.VirtualAccount {
+	partner := types.BankingPartnerJpmorgan
+	account := &virtualaccounts.VirtualAccount{
+		ID:                 VirtualAccountID,
+		Token:              types.StablecoinTokenUSDC,
+		BlockchainWalletID: BlockchainWalletID,
+		BankingPartner:     &partner,
+		KycStatus:          &kycStatus,
+	}
+	account.US.ACH.RoutingNumber = "021000021"
+	account.US.ACH.AccountNumber = "9876543210"
+	account.US.Wire.RoutingNumber = "021000021"
+	account.US.Wire.AccountNumber = "9876543210"
+	account.US.RTP.RoutingNumber = "021000021"
+	account.US.RTP.AccountNumber = "9876543210"
+	account.US.SwiftBICCode = "CHASUS33"
+	account.US.AccountType = "checking"
+	account.US.Beneficiary.Name = "Maria Souza"
+	account.US.Beneficiary.AddressLine1 = "Av. Paulista, 1578"
+	account.US.ReceivingBank.Name = "JPMorgan Chase Bank, N.A."
+	account.US.ReceivingBank.AddressLine1 = "383 Madison Avenue"
+	account.US.ReceivingBank.AddressLine2 = "New York, NY 10179"
+	return account
+}
+
+func transfer() *transfers.Transfer {
+	return &transfers.Transfer{
+		ID:              TransferID,
+		TransferQuoteID: "tq_Bk4mV9xRc2Wn",
+		InstanceID:      InstanceID,
+		TrackingTransactionMonitoring: transfers.TrackingTransactionMonitoring{
+			Step: "completed",
+		},
+		CreatedAt:             Time,
+		UpdatedAt:             Time,
+		FirstName:             "Maria",
+		LastName:              "Souza",
+		WalletID:              CustodialWalletID,
+		SenderToken:           types.StablecoinTokenUSDC,
+		SenderAmount:          100000,
+		ReceiverAmount:        99900,
+		ReceiverNetwork:       types.NetworkPolygon,
+		ReceiverToken:         types.StablecoinTokenUSDC,
+		ReceiverWalletAddress: "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+		ReceiverID:            ReceiverID,
+		Network:               types.NetworkBase,
+	}
+}
+
+func transferStep(step string, completedAt time.Time) transfers.TrackingStep {
+	at := completedAt.Format(time.RFC3339)
+	hash := "0x4a6c8e0b2d4f6a8c0e2b4d6f8a0c2e4b6d8f0a2c4e6b8d0f2a4c6e8b0d2f4a6c"
+	return transfers.TrackingStep{
+		Step:            step,
+		TransactionHash: &hash,
+		CompletedAt:     &at,
+	}
+}
@@ -0,0 +1,83 @@
+package webhooktest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	blindpay "github.com/blindpaylabs/blindpay-go"
+	"github.com/blindpaylabs/blindpay-go/internal/types"
+	"github.com/blindpaylabs/blindpay-go/payouts"
+	"github.com/blindpaylabs/blindpay-go/receivers"
+)
+
+const secret = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+
+func TestExamplesParse(t *testing.T) {
+	for _, event := range Events() {
+		t.Run(string(event), func(t *testing.T) {
+			req, err := NewRequest("http://localhost/webhooks", secret, event, nil)
+			require.NoError(t, err)
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+
+			envelope, err := blindpay.ParseWebhookEvent(secret, req.Header, body)
+			require.NoError(t, err)
+			require.Equal(t, event, envelope.Type)
+
+			// Only the events without an SDK model stay raw.
+			_, raw := envelope.Data.(json.RawMessage)
+			require.Equal(t, event == types.WebhookEventTosAccept || event == types.WebhookEventWalletInbound, raw)
+		})
+	}
+}
+
+func TestExample_Unknown(t *testing.T) {
+	_, err := Example("instance.update")
+	require.Error(t, err)
+}
+
+func TestNewRequest(t *testing.T) {
+	var gotPayout *payouts.Payout
+	var gotReceiver *receivers.Receiver
+	srv := httptest.NewServer(blindpay.NewWebhookHandler(secret,
+		blindpay.OnPayoutUpdate(func(ctx context.Context, payout *payouts.Payout) error {
+			gotPayout = payout
+			return nil
+		}),
+		blindpay.OnReceiverUpdate(func(ctx context.Context, receiver *receivers.Receiver) error {
+			gotReceiver = receiver
+			return nil
+		}),
+	))
+	defer srv.Close()
+
+	req, err := NewRequest(srv.URL, secret, blindpay.WebhookEventPayoutUpdate, nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	require.NotNil(t, gotPayout)
+	require.Equal(t, PayoutID, gotPayout.ID)
+	require.Equal(t, types.TransactionStatusProcessing, gotPayout.Status)
+
+	data, err := Example(blindpay.WebhookEventReceiverUpdate)
+	require.NoError(t, err)
+	receiver := data.(*receivers.Receiver)
+	receiver.KycStatus = "rejected"
+
+	req, err = NewRequest(srv.URL, secret, blindpay.WebhookEventReceiverUpdate, receiver)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	require.NotNil(t, gotReceiver)
+	require.Equal(t, "rejected", gotReceiver.KycStatus)
+}